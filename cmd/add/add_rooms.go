@@ -10,13 +10,12 @@ package add
 import (
 	"errors"
 	"fmt"
-	"net/http"
 	"strconv"
 
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 )
@@ -34,19 +33,30 @@ var addRoomsCmd = &cobra.Command{
 			return err
 		}
 
-		return NewAddRooms(client, config).run(cmd, args)
+		err = common.Confirm(config, fmt.Sprintf("Add %s rooms to scheduler %s", args[1], args[0]))
+		if err != nil {
+			return err
+		}
+
+		return NewAddRooms(client, config, common.GetWaitParameters(cmd, config)).run(cmd, args)
 	},
 }
 
 type AddRooms struct {
-	client interfaces.Client
-	config *extensions.Config
+	client maestro.Client
+	config *extensions.ContextConfig
+	wait   *common.WaitParameters
+}
+
+func init() {
+	common.AddWaitFlags(addRoomsCmd)
 }
 
-func NewAddRooms(client interfaces.Client, config *extensions.Config) *AddRooms {
+func NewAddRooms(client maestro.Client, config *extensions.ContextConfig, wait *common.WaitParameters) *AddRooms {
 	return &AddRooms{
 		client: client,
 		config: config,
+		wait:   wait,
 	}
 }
 
@@ -62,33 +72,26 @@ func validateArgs(_ *cobra.Command, args []string) error {
 	return nil
 }
 
-func (a *AddRooms) run(_ *cobra.Command, args []string) error {
+func (a *AddRooms) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
 
 	logger := common.GetLogger()
 	schedulerName := args[0]
 	roomsAmount, _ := strconv.ParseInt(args[1], 10, 32)
 
-	request := v1.AddRoomsRequest{
-		Amount: int32(roomsAmount),
-	}
-
-	serializedRequest, err := common.Marshaller.Marshal(&request)
-	if err != nil {
-		return fmt.Errorf("error parsing request to json: %w", err)
+	request := &v1.AddRoomsRequest{
+		SchedulerName: schedulerName,
+		Amount:        int32(roomsAmount),
 	}
 
 	logger.Debug("addding rooms to scheduler: " + schedulerName)
 
-	url := fmt.Sprintf("%s/schedulers/%s/add-rooms", a.config.ServerURL, schedulerName)
-	body, status, err := a.client.Post(url, string(serializedRequest))
+	response, err := a.client.AddRooms(ctx, request)
 	if err != nil {
-		return fmt.Errorf("error on post request: %w", err)
-	}
-	if status != http.StatusOK {
-		return fmt.Errorf("add rooms response not ok, status: %s, body: %s", http.StatusText(status), string(body))
+		return err
 	}
 
 	logger.Info("Successfully rooms added: " + schedulerName)
 
-	return nil
+	return common.WaitOperation(ctx, a.client, a.wait, schedulerName, response.GetOperationId())
 }
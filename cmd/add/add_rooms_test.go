@@ -12,8 +12,9 @@ import (
 	"testing"
 
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/protobuf/encoding/protojson"
 
-	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -23,7 +24,7 @@ import (
 
 func TestAddRoomsAction(t *testing.T) {
 
-	config := &extensions.Config{
+	config := &extensions.ContextConfig{
 		ServerURL: "http://localhost:8080",
 	}
 
@@ -63,12 +64,12 @@ func TestAddRoomsAction(t *testing.T) {
 			Amount: int32(10),
 		}
 
-		serializedRequest, err := common.Marshaller.Marshal(&request)
+		serializedRequest, err := protojson.Marshal(&request)
 		require.NoError(t, err)
 
-		client.EXPECT().Post(config.ServerURL+"/schedulers/scheduler/add-rooms", string(serializedRequest)).Return([]byte(""), 200, nil)
+		client.EXPECT().IdempotentPost(gomock.Any(), config.ServerURL+"/schedulers/scheduler/add-rooms", string(serializedRequest), gomock.Any()).Return([]byte("{}"), 200, nil)
 
-		err = NewAddRooms(client, config).run(nil, []string{"scheduler", "10"})
+		err = NewAddRooms(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler", "10"})
 
 		require.NoError(t, err)
 	})
@@ -84,15 +85,15 @@ func TestAddRoomsAction(t *testing.T) {
 			Amount: int32(10),
 		}
 
-		serializedRequest, err := common.Marshaller.Marshal(&request)
+		serializedRequest, err := protojson.Marshal(&request)
 		require.NoError(t, err)
 
-		client.EXPECT().Post(config.ServerURL+"/schedulers/scheduler/add-rooms", string(serializedRequest)).Return([]byte(""), 0, fmt.Errorf("tcp connection failed"))
+		client.EXPECT().IdempotentPost(gomock.Any(), config.ServerURL+"/schedulers/scheduler/add-rooms", string(serializedRequest), gomock.Any()).Return([]byte(""), 0, fmt.Errorf("tcp connection failed"))
 
-		err = NewAddRooms(client, config).run(nil, []string{"scheduler", "10"})
+		err = NewAddRooms(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler", "10"})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "error on post request: tcp connection failed")
+		require.Contains(t, err.Error(), "error on POST request: tcp connection failed")
 	})
 
 	t.Run("fails when maestro API fails", func(t *testing.T) {
@@ -106,14 +107,14 @@ func TestAddRoomsAction(t *testing.T) {
 			Amount: int32(10),
 		}
 
-		serializedRequest, err := common.Marshaller.Marshal(&request)
+		serializedRequest, err := protojson.Marshal(&request)
 		require.NoError(t, err)
 
-		client.EXPECT().Post(config.ServerURL+"/schedulers/scheduler/add-rooms", string(serializedRequest)).Return([]byte(""), 404, nil)
+		client.EXPECT().IdempotentPost(gomock.Any(), config.ServerURL+"/schedulers/scheduler/add-rooms", string(serializedRequest), gomock.Any()).Return([]byte(""), 404, nil)
 
-		err = NewAddRooms(client, config).run(nil, []string{"scheduler", "10"})
+		err = NewAddRooms(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler", "10"})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "add rooms response not ok, status: Not Found, body: ")
+		require.Contains(t, err.Error(), "add rooms failed with status Not Found")
 	})
 }
@@ -10,14 +10,11 @@ package cancel
 import (
 	"errors"
 	"fmt"
-	"net/http"
-
-	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 )
@@ -35,48 +32,39 @@ var cancelOperationCmd = &cobra.Command{
 			return err
 		}
 
+		err = common.Confirm(config, fmt.Sprintf("Cancel operation %s of scheduler %s", args[1], args[0]))
+		if err != nil {
+			return err
+		}
+
 		return NewCancelOperation(client, config).run(cmd, args)
 	},
 }
 
 type CancelOperation struct {
-	client interfaces.Client
-	config *extensions.Config
+	client maestro.Client
+	config *extensions.ContextConfig
 }
 
-func NewCancelOperation(client interfaces.Client, config *extensions.Config) *CancelOperation {
+func NewCancelOperation(client maestro.Client, config *extensions.ContextConfig) *CancelOperation {
 	return &CancelOperation{
 		client: client,
 		config: config,
 	}
 }
 
-func (a *CancelOperation) run(_ *cobra.Command, args []string) error {
+func (a *CancelOperation) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
 
 	logger := common.GetLogger()
 	schedulerName := args[0]
 	operationID := args[1]
 
-	request := v1.CancelOperationRequest{}
-	serializedRequest, err := protojson.Marshal(&request)
-	if err != nil {
-		return fmt.Errorf("error parsing request to json: %w", err)
-	}
-
 	logger.Sugar().Debugf("cancel operation %s from scheduler %s", schedulerName, operationID)
 
-	url := fmt.Sprintf("%s/schedulers/%s/operations/%s/cancel", a.config.ServerURL, schedulerName, operationID)
-	body, status, err := a.client.Post(url, string(serializedRequest))
-	if err != nil {
-		return fmt.Errorf("error on post request: %w", err)
-	}
-	if status != http.StatusOK {
-		return fmt.Errorf("cancel operation response not ok, status: %s, body: %s", http.StatusText(status), string(body))
-	}
-	var response v1.CancelOperationResponse
-	err = protojson.Unmarshal(body, &response)
+	_, err := a.client.CancelOperation(ctx, &v1.CancelOperationRequest{SchedulerName: schedulerName, OperationId: operationID})
 	if err != nil {
-		return fmt.Errorf("error deserializing cancel operation response, details: %w", err)
+		return err
 	}
 
 	logger.Info("cancel operation request successfully sent")
@@ -16,11 +16,12 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 )
 
 func TestCancelOperationAction(t *testing.T) {
 
-	config := &extensions.Config{
+	config := &extensions.ContextConfig{
 		ServerURL: "http://localhost:8080",
 	}
 
@@ -53,9 +54,9 @@ func TestCancelOperationAction(t *testing.T) {
 		client := mocks.NewMockClient(mockCtrl)
 
 		url := fmt.Sprintf("%s/schedulers/%s/operations/%s/cancel", config.ServerURL, schedulerName, operationID)
-		client.EXPECT().Post(url, gomock.Any()).Return([]byte("{}"), 200, nil)
+		client.EXPECT().Post(gomock.Any(), url, gomock.Any()).Return([]byte("{}"), 200, nil)
 
-		err := NewCancelOperation(client, config).run(nil, []string{schedulerName, operationID})
+		err := NewCancelOperation(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName, operationID})
 
 		require.NoError(t, err)
 	})
@@ -71,12 +72,12 @@ func TestCancelOperationAction(t *testing.T) {
 		client := mocks.NewMockClient(mockCtrl)
 
 		url := fmt.Sprintf("%s/schedulers/%s/operations/%s/cancel", config.ServerURL, schedulerName, operationID)
-		client.EXPECT().Post(url, gomock.Any()).Return([]byte(""), 200, nil)
+		client.EXPECT().Post(gomock.Any(), url, gomock.Any()).Return([]byte(""), 200, nil)
 
-		err := NewCancelOperation(client, config).run(nil, []string{schedulerName, operationID})
+		err := NewCancelOperation(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName, operationID})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "error deserializing cancel operation response")
+		require.Contains(t, err.Error(), "error parsing response body of cancel operation")
 	})
 
 	t.Run("fails when HTTP request fails", func(t *testing.T) {
@@ -90,12 +91,12 @@ func TestCancelOperationAction(t *testing.T) {
 		client := mocks.NewMockClient(mockCtrl)
 
 		url := fmt.Sprintf("%s/schedulers/%s/operations/%s/cancel", config.ServerURL, schedulerName, operationID)
-		client.EXPECT().Post(url, gomock.Any()).Return([]byte(""), 0, fmt.Errorf("tcp connection failed"))
+		client.EXPECT().Post(gomock.Any(), url, gomock.Any()).Return([]byte(""), 0, fmt.Errorf("tcp connection failed"))
 
-		err := NewCancelOperation(client, config).run(nil, []string{schedulerName, operationID})
+		err := NewCancelOperation(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName, operationID})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "error on post request: tcp connection failed")
+		require.Contains(t, err.Error(), "error on POST request: tcp connection failed")
 	})
 
 	t.Run("fails when maestro API fails", func(t *testing.T) {
@@ -109,11 +110,11 @@ func TestCancelOperationAction(t *testing.T) {
 		client := mocks.NewMockClient(mockCtrl)
 
 		url := fmt.Sprintf("%s/schedulers/%s/operations/%s/cancel", config.ServerURL, schedulerName, operationID)
-		client.EXPECT().Post(url, gomock.Any()).Return([]byte(""), 400, nil)
+		client.EXPECT().Post(gomock.Any(), url, gomock.Any()).Return([]byte(""), 400, nil)
 
-		err := NewCancelOperation(client, config).run(nil, []string{schedulerName, operationID})
+		err := NewCancelOperation(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName, operationID})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "cancel operation response not ok, status: Bad Request, body: ")
+		require.Contains(t, err.Error(), "cancel operation failed with status Bad Request")
 	})
 }
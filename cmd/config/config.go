@@ -0,0 +1,23 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the config command
+var Cmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspects maestro-cli configuration",
+	Long:  `Inspects maestro-cli configuration, to know more type maestro-cli config --help.`,
+}
+
+func init() {
+	Cmd.AddCommand(viewConfigCmd)
+}
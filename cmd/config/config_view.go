@@ -0,0 +1,98 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/interfaces"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var viewResolved bool
+
+// viewConfigCmd represents the config view command
+var viewConfigCmd = &cobra.Command{
+	Use:     "view",
+	Short:   "Shows the configuration",
+	Example: "maestro-cli config view --resolved",
+	Long: `Shows the config file with secrets redacted. With --resolved shows the effective settings of the selected context and where each value came from.
+Settings are resolved with the precedence flag > env > context file > defaults, using the flags --context, --server and --token and the env vars MAESTRO_CONTEXT, MAESTRO_SERVER_URL, MAESTRO_TOKEN and MAESTRO_TIMEOUT.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewViewConfig(extensions.NewFileSystem(), os.Stdout, viewResolved).run(cmd, args)
+	},
+}
+
+func init() {
+	viewConfigCmd.Flags().BoolVar(&viewResolved, "resolved", false, "Shows the effective settings and their sources")
+}
+
+type ViewConfig struct {
+	fs       interfaces.FileSystem
+	out      io.Writer
+	resolved bool
+}
+
+func NewViewConfig(fs interfaces.FileSystem, out io.Writer, resolved bool) *ViewConfig {
+	return &ViewConfig{
+		fs:       fs,
+		out:      out,
+		resolved: resolved,
+	}
+}
+
+func (v *ViewConfig) run(_ *cobra.Command, _ []string) error {
+	if v.resolved {
+		return v.printResolved()
+	}
+
+	config, err := extensions.ReadConfig(v.fs)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+	err = config.Check()
+	if err != nil {
+		return err
+	}
+
+	bts, err := yaml.Marshal(config.Redacted())
+	if err != nil {
+		return fmt.Errorf("error serializing config: %w", err)
+	}
+	_, err = v.out.Write(bts)
+	return err
+}
+
+func (v *ViewConfig) printResolved() error {
+	resolved, err := common.ResolveConfig(v.fs)
+	if err != nil {
+		return err
+	}
+
+	w := new(tabwriter.Writer)
+
+	// minwidth, tabwidth, padding, padchar, flags
+	w.Init(v.out, 8, 8, 0, '\t', 0)
+
+	defer w.Flush()
+
+	format := "%s\t\t%s\t\t%s\t\n"
+	fmt.Fprintf(w, format, "SETTING", "VALUE", "SOURCE")
+
+	for _, setting := range resolved.Settings {
+		fmt.Fprintf(w, format, setting.Name, setting.Value, setting.Source)
+	}
+
+	return nil
+}
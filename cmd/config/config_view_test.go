@@ -0,0 +1,200 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package config
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+const configFile = `version: 1
+currentContext: prod
+contexts:
+  prod:
+    serverUrl: https://maestro.example.com
+    auth:
+      token: super-secret
+    timeout: 1m
+`
+
+func setEnv(t *testing.T, key, value string) {
+	require.NoError(t, os.Setenv(key, value))
+	t.Cleanup(func() { os.Unsetenv(key) })
+}
+
+func TestViewConfigAction(t *testing.T) {
+	t.Run("shows config file with secrets redacted", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		out := new(bytes.Buffer)
+		err := NewViewConfig(fs, out, false).run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "token: REDACTED")
+		require.NotContains(t, out.String(), "super-secret")
+	})
+
+	t.Run("fails when a context of the config file is broken", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile+"  broken:\n    serverUrl: not-a-url\n"), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		err := NewViewConfig(fs, new(bytes.Buffer), false).run(nil, []string{})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `context "broken": bad serverUrl "not-a-url"`)
+	})
+
+	t.Run("resolves the context in use when another one is broken", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile+"  broken:\n    serverUrl: not-a-url\n"), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		out := new(bytes.Buffer)
+		err := NewViewConfig(fs, out, true).run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Regexp(t, "serverUrl\t+https://maestro.example.com\t+context file", out.String())
+	})
+
+	t.Run("shows resolved settings with flag > env > context file > defaults", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		common.ServerURL = "http://localhost:8080"
+		common.RetryMaxAttempts = 2
+		defer func() {
+			common.ServerURL = ""
+			common.RetryMaxAttempts = 0
+		}()
+		setEnv(t, common.EnvServerURL, "http://env.example.com")
+		setEnv(t, common.EnvToken, "env-token")
+
+		out := new(bytes.Buffer)
+		err := NewViewConfig(fs, out, true).run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Regexp(t, "context\t+prod\t+context file", out.String())
+		require.Regexp(t, "serverUrl\t+http://localhost:8080\t+flag", out.String())
+		require.Regexp(t, "auth\t+token\t+env", out.String())
+		require.Regexp(t, "timeout\t+1m0s\t+context file", out.String())
+		require.Regexp(t, "retry.maxAttempts\t+2\t+flag", out.String())
+		require.Regexp(t, "retry.maxElapsedTime\t+1m0s\t+default", out.String())
+		require.NotContains(t, out.String(), "env-token")
+	})
+
+	t.Run("resolves settings without config file", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return(nil, os.ErrNotExist)
+		fs.EXPECT().IsNotExist(os.ErrNotExist).Return(true)
+		fs.EXPECT().Glob(gomock.Any()).Return(nil, nil)
+
+		setEnv(t, common.EnvServerURL, "http://env.example.com")
+		setEnv(t, common.EnvTimeout, "30s")
+
+		out := new(bytes.Buffer)
+		err := NewViewConfig(fs, out, true).run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Regexp(t, "context\t+-\t+default", out.String())
+		require.Regexp(t, "serverUrl\t+http://env.example.com\t+env", out.String())
+		require.Regexp(t, "auth\t+none\t+default", out.String())
+		require.Regexp(t, "timeout\t+30s\t+env", out.String())
+	})
+
+	t.Run("selects context from env", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		setEnv(t, common.EnvContext, "staging")
+
+		err := NewViewConfig(fs, new(bytes.Buffer), true).run(nil, []string{})
+
+		require.Error(t, err)
+		require.Equal(t, "context \"staging\" not found", err.Error())
+	})
+
+	t.Run("fails when timeout env is not a duration", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		setEnv(t, common.EnvTimeout, "ten")
+
+		err := NewViewConfig(fs, new(bytes.Buffer), true).run(nil, []string{})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "bad MAESTRO_TIMEOUT value")
+		require.Equal(t, common.ExitValidation, common.ExitCode(err))
+	})
+
+	t.Run("fails when the server flag is not a maestro URL", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		common.ServerURL = "localhost:8080"
+		defer func() { common.ServerURL = "" }()
+
+		err := NewViewConfig(fs, new(bytes.Buffer), true).run(nil, []string{})
+
+		require.EqualError(t, err, `bad --server value: bad serverUrl "localhost:8080"`)
+		require.Equal(t, common.ExitValidation, common.ExitCode(err))
+	})
+
+	t.Run("fails when the server env is not a maestro URL", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return(nil, os.ErrNotExist)
+		fs.EXPECT().IsNotExist(os.ErrNotExist).Return(true)
+		fs.EXPECT().Glob(gomock.Any()).Return(nil, nil)
+
+		setEnv(t, common.EnvServerURL, "ftp://maestro.example.com")
+
+		err := NewViewConfig(fs, new(bytes.Buffer), true).run(nil, []string{})
+
+		require.EqualError(t, err, `bad MAESTRO_SERVER_URL value: bad serverUrl "ftp://maestro.example.com"`)
+		require.Equal(t, common.ExitValidation, common.ExitCode(err))
+	})
+}
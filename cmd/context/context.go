@@ -0,0 +1,27 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the context command
+var Cmd = &cobra.Command{
+	Use:   "context",
+	Short: "Manages maestro-cli contexts",
+	Long:  `Lists, selects, renames and deletes the contexts stored in ~/.maestro/config.yaml, to know more type maestro-cli context --help.`,
+}
+
+func init() {
+	Cmd.AddCommand(listContextsCmd)
+	Cmd.AddCommand(useContextCmd)
+	Cmd.AddCommand(currentContextCmd)
+	Cmd.AddCommand(renameContextCmd)
+	Cmd.AddCommand(deleteContextCmd)
+}
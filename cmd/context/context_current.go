@@ -0,0 +1,56 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/interfaces"
+)
+
+// currentContextCmd represents the context current command
+var currentContextCmd = &cobra.Command{
+	Use:     "current",
+	Short:   "Shows the current context",
+	Example: "maestro-cli context current",
+	Long:    "Prints the name of the context used by every command that is not called with the --context flag.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewCurrentContext(extensions.NewFileSystem(), os.Stdout).run(cmd, args)
+	},
+}
+
+type CurrentContext struct {
+	fs  interfaces.FileSystem
+	out io.Writer
+}
+
+func NewCurrentContext(fs interfaces.FileSystem, out io.Writer) *CurrentContext {
+	return &CurrentContext{
+		fs:  fs,
+		out: out,
+	}
+}
+
+func (c *CurrentContext) run(_ *cobra.Command, _ []string) error {
+	config, err := extensions.ReadConfig(c.fs)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	if config.CurrentContext == "" {
+		return errors.New("current context is not set, use maestro-cli context use to set it")
+	}
+
+	fmt.Fprintln(c.out, config.CurrentContext)
+	return nil
+}
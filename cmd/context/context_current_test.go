@@ -0,0 +1,46 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+func TestCurrentContextAction(t *testing.T) {
+	t.Run("with success", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+
+		out := new(bytes.Buffer)
+		err := NewCurrentContext(fs, out).run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Equal(t, "prod\n", out.String())
+	})
+
+	t.Run("fails when current context is not set", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, "version: 1\ncontexts:\n  local:\n    serverUrl: http://localhost:8080\n")
+
+		err := NewCurrentContext(fs, new(bytes.Buffer)).run(nil, []string{})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "current context is not set")
+	})
+}
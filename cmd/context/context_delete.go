@@ -0,0 +1,73 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/interfaces"
+)
+
+// deleteContextCmd represents the context delete command
+var deleteContextCmd = &cobra.Command{
+	Use:     "delete",
+	Short:   "Deletes a context",
+	Example: "maestro-cli context delete <context_name>",
+	Long:    "Deletes a context from the config file, if it is the current context no context remains selected.",
+	Args:    validateDeleteArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewDeleteContext(extensions.NewFileSystem(), os.Stdout).run(cmd, args)
+	},
+}
+
+type DeleteContext struct {
+	fs  interfaces.FileSystem
+	out io.Writer
+}
+
+func NewDeleteContext(fs interfaces.FileSystem, out io.Writer) *DeleteContext {
+	return &DeleteContext{
+		fs:  fs,
+		out: out,
+	}
+}
+
+func validateDeleteArgs(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("missing arg: context name")
+	}
+
+	return nil
+}
+
+func (d *DeleteContext) run(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	config, err := extensions.ReadConfig(d.fs)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	err = config.Delete(name)
+	if err != nil {
+		return err
+	}
+
+	err = config.Write(d.fs)
+	if err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+
+	fmt.Fprintf(d.out, "Context %q deleted\n", name)
+	return nil
+}
@@ -0,0 +1,71 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+func TestDeleteContextAction(t *testing.T) {
+	t.Run("fails when not enough args", func(t *testing.T) {
+		err := validateDeleteArgs(nil, []string{})
+
+		require.Error(t, err)
+		require.Equal(t, "missing arg: context name", err.Error())
+	})
+
+	t.Run("deletes the current context with success", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+		written := expectConfigWrite(t, fs)
+
+		out := new(bytes.Buffer)
+		err := NewDeleteContext(fs, out).run(nil, []string{"prod"})
+
+		require.NoError(t, err)
+		require.NotContains(t, written(), "currentContext")
+		require.NotContains(t, written(), "prod")
+		require.Contains(t, written(), "local")
+		require.Equal(t, "Context \"prod\" deleted\n", out.String())
+	})
+
+	t.Run("deletes a broken context", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile+"  broken:\n    serverUrl: not-a-url\n")
+		written := expectConfigWrite(t, fs)
+
+		err := NewDeleteContext(fs, new(bytes.Buffer)).run(nil, []string{"broken"})
+
+		require.NoError(t, err)
+		require.NotContains(t, written(), "broken")
+		require.Contains(t, written(), "prod")
+	})
+	t.Run("fails when context does not exist", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+
+		err := NewDeleteContext(fs, new(bytes.Buffer)).run(nil, []string{"staging"})
+
+		require.Error(t, err)
+		require.Equal(t, "context \"staging\" not found", err.Error())
+	})
+}
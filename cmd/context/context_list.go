@@ -0,0 +1,75 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/interfaces"
+)
+
+// listContextsCmd represents the context list command
+var listContextsCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "Lists all contexts",
+	Example: "maestro-cli context list",
+	Long:    "Lists all contexts, the current one is marked with an asterisk.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewListContexts(extensions.NewFileSystem(), os.Stdout).run(cmd, args)
+	},
+}
+
+type ListContexts struct {
+	fs  interfaces.FileSystem
+	out io.Writer
+}
+
+func NewListContexts(fs interfaces.FileSystem, out io.Writer) *ListContexts {
+	return &ListContexts{
+		fs:  fs,
+		out: out,
+	}
+}
+
+func (l *ListContexts) run(_ *cobra.Command, _ []string) error {
+	config, err := extensions.ReadConfig(l.fs)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	if len(config.Contexts) == 0 {
+		fmt.Fprintln(l.out, "no contexts found, use maestro-cli init to create one")
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+
+	// minwidth, tabwidth, padding, padchar, flags
+	w.Init(l.out, 8, 8, 0, '\t', 0)
+
+	defer w.Flush()
+
+	format := "%s\t\t%s\t\t%s\t\n"
+	fmt.Fprintf(w, format, "CURRENT", "NAME", "SERVER_URL")
+
+	for _, name := range config.ContextNames() {
+		current := ""
+		if name == config.CurrentContext {
+			current = "*"
+		}
+		fmt.Fprintf(w, format, current, name, config.Contexts[name].ServerURL)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+func TestListContextsAction(t *testing.T) {
+	t.Run("with success", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+
+		out := new(bytes.Buffer)
+		err := NewListContexts(fs, out).run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "local")
+		require.Regexp(t, `\*\t+prod`, out.String())
+		require.Regexp(t, "(?s)local.*prod", out.String())
+	})
+
+	t.Run("migrates legacy config files when config file does not exist", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return(nil, os.ErrNotExist)
+		fs.EXPECT().IsNotExist(os.ErrNotExist).Return(true)
+		fs.EXPECT().Glob(gomock.Any()).Return([]string{"/home/user/.maestro/config-prod.yaml"}, nil)
+		fs.EXPECT().ReadFile("/home/user/.maestro/config-prod.yaml").Return([]byte("serverUrl: https://maestro.example.com\n"), nil)
+		written := expectConfigWrite(t, fs)
+		fs.EXPECT().Rename("/home/user/.maestro/config-prod.yaml", "/home/user/.maestro/config-prod.yaml.bak").Return(nil)
+
+		out := new(bytes.Buffer)
+		err := NewListContexts(fs, out).run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Contains(t, written(), "version: 1\ncurrentContext: prod\n")
+		require.Regexp(t, `\*\t+prod\t+https://maestro.example.com`, out.String())
+	})
+
+	t.Run("when there are no contexts", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return(nil, os.ErrNotExist)
+		fs.EXPECT().IsNotExist(os.ErrNotExist).Return(true)
+		fs.EXPECT().Glob(gomock.Any()).Return([]string{}, nil)
+
+		out := new(bytes.Buffer)
+		err := NewListContexts(fs, out).run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Equal(t, "no contexts found, use maestro-cli init to create one\n", out.String())
+	})
+
+	t.Run("fails when config file can not be read", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		readErr := errors.New("permission denied")
+		fs.EXPECT().ReadFile(gomock.Any()).Return(nil, readErr)
+		fs.EXPECT().IsNotExist(readErr).Return(false)
+
+		err := NewListContexts(fs, new(bytes.Buffer)).run(nil, []string{})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error reading config file: permission denied")
+	})
+}
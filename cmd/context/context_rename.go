@@ -0,0 +1,74 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/interfaces"
+)
+
+// renameContextCmd represents the context rename command
+var renameContextCmd = &cobra.Command{
+	Use:     "rename",
+	Short:   "Renames a context",
+	Example: "maestro-cli context rename <old_name> <new_name>",
+	Long:    "Renames a context, if it is the current context it remains current under its new name.",
+	Args:    validateRenameArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewRenameContext(extensions.NewFileSystem(), os.Stdout).run(cmd, args)
+	},
+}
+
+type RenameContext struct {
+	fs  interfaces.FileSystem
+	out io.Writer
+}
+
+func NewRenameContext(fs interfaces.FileSystem, out io.Writer) *RenameContext {
+	return &RenameContext{
+		fs:  fs,
+		out: out,
+	}
+}
+
+func validateRenameArgs(_ *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return errors.New("missing args: old context name or/and new context name")
+	}
+
+	return nil
+}
+
+func (r *RenameContext) run(_ *cobra.Command, args []string) error {
+	oldName := args[0]
+	newName := args[1]
+
+	config, err := extensions.ReadConfig(r.fs)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	err = config.Rename(oldName, newName)
+	if err != nil {
+		return err
+	}
+
+	err = config.Write(r.fs)
+	if err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+
+	fmt.Fprintf(r.out, "Context %q renamed to %q\n", oldName, newName)
+	return nil
+}
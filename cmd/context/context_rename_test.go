@@ -0,0 +1,55 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+func TestRenameContextAction(t *testing.T) {
+	t.Run("fails when not enough args", func(t *testing.T) {
+		err := validateRenameArgs(nil, []string{"prod"})
+
+		require.Error(t, err)
+		require.Equal(t, "missing args: old context name or/and new context name", err.Error())
+	})
+
+	t.Run("renames the current context with success", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+		written := expectConfigWrite(t, fs)
+
+		err := NewRenameContext(fs, new(bytes.Buffer)).run(nil, []string{"prod", "production"})
+
+		require.NoError(t, err)
+		require.Contains(t, written(), "currentContext: production")
+		require.Contains(t, written(), "  production:\n    serverUrl: https://maestro.example.com")
+		require.NotContains(t, written(), "  prod:")
+	})
+
+	t.Run("fails when new name is already used", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+
+		err := NewRenameContext(fs, new(bytes.Buffer)).run(nil, []string{"prod", "local"})
+
+		require.Error(t, err)
+		require.Equal(t, "context \"local\" already exists", err.Error())
+	})
+}
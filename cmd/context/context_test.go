@@ -0,0 +1,50 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+const configFile = `version: 1
+currentContext: prod
+contexts:
+  local:
+    serverUrl: http://localhost:8080
+  prod:
+    serverUrl: https://maestro.example.com
+`
+
+// expectConfigRead makes fs return content as the config file.
+func expectConfigRead(fs *mocks.MockFileSystem, content string) {
+	fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(content), nil)
+	fs.EXPECT().IsNotExist(nil).Return(false)
+}
+
+// expectConfigWrite makes fs write the config file into memory, the returned
+// function reads back what was written.
+func expectConfigWrite(t *testing.T, fs *mocks.MockFileSystem) func() string {
+	memFs := afero.NewMemMapFs()
+	file, err := memFs.Create("config.yaml")
+	require.NoError(t, err)
+
+	fs.EXPECT().MkdirAll(gomock.Any(), extensions.ConfigDirPerm).Return(nil)
+	fs.EXPECT().OpenFile(gomock.Any(), extensions.ConfigFileFlag, extensions.ConfigFilePerm).Return(file, nil)
+
+	return func() string {
+		content, err := afero.ReadFile(memFs, "config.yaml")
+		require.NoError(t, err)
+		return string(content)
+	}
+}
@@ -0,0 +1,73 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/interfaces"
+)
+
+// useContextCmd represents the context use command
+var useContextCmd = &cobra.Command{
+	Use:     "use",
+	Short:   "Sets the current context",
+	Example: "maestro-cli context use <context_name>",
+	Long:    "Sets the context used by every command that is not called with the --context flag.",
+	Args:    validateUseArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewUseContext(extensions.NewFileSystem(), os.Stdout).run(cmd, args)
+	},
+}
+
+type UseContext struct {
+	fs  interfaces.FileSystem
+	out io.Writer
+}
+
+func NewUseContext(fs interfaces.FileSystem, out io.Writer) *UseContext {
+	return &UseContext{
+		fs:  fs,
+		out: out,
+	}
+}
+
+func validateUseArgs(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("missing arg: context name")
+	}
+
+	return nil
+}
+
+func (u *UseContext) run(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	config, err := extensions.ReadConfig(u.fs)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	err = config.Use(name)
+	if err != nil {
+		return err
+	}
+
+	err = config.Write(u.fs)
+	if err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+
+	fmt.Fprintf(u.out, "Switched to context %q\n", name)
+	return nil
+}
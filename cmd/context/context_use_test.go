@@ -0,0 +1,55 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package context
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+func TestUseContextAction(t *testing.T) {
+	t.Run("fails when not enough args", func(t *testing.T) {
+		err := validateUseArgs(nil, []string{})
+
+		require.Error(t, err)
+		require.Equal(t, "missing arg: context name", err.Error())
+	})
+
+	t.Run("with success", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+		written := expectConfigWrite(t, fs)
+
+		out := new(bytes.Buffer)
+		err := NewUseContext(fs, out).run(nil, []string{"local"})
+
+		require.NoError(t, err)
+		require.Contains(t, written(), "currentContext: local")
+		require.Equal(t, "Switched to context \"local\"\n", out.String())
+	})
+
+	t.Run("fails when context does not exist", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+
+		err := NewUseContext(fs, new(bytes.Buffer)).run(nil, []string{"staging"})
+
+		require.Error(t, err)
+		require.Equal(t, "context \"staging\" not found", err.Error())
+	})
+}
@@ -11,12 +11,11 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
@@ -36,16 +35,21 @@ var CreateSchedulerCmd = &cobra.Command{
 			return err
 		}
 
+		err = common.Confirm(config, fmt.Sprintf("Create scheduler(s) from %s", args[0]))
+		if err != nil {
+			return err
+		}
+
 		return NewCreateScheduler(client, config).run(cmd, args)
 	},
 }
 
 type CreateScheduler struct {
-	client interfaces.Client
-	config *extensions.Config
+	client maestro.Client
+	config *extensions.ContextConfig
 }
 
-func NewCreateScheduler(client interfaces.Client, config *extensions.Config) *CreateScheduler {
+func NewCreateScheduler(client maestro.Client, config *extensions.ContextConfig) *CreateScheduler {
 	return &CreateScheduler{
 		client: client,
 		config: config,
@@ -66,7 +70,8 @@ func validateArgs(_ *cobra.Command, args []string) error {
 	return nil
 }
 
-func (cs *CreateScheduler) run(_ *cobra.Command, args []string) error {
+func (cs *CreateScheduler) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
 
 	logger := common.GetLogger()
 	filePath := args[0]
@@ -77,36 +82,27 @@ func (cs *CreateScheduler) run(_ *cobra.Command, args []string) error {
 
 	yamls, err := common.SplitYAML(bts)
 	if err != nil {
-		return fmt.Errorf("error splitting YAML file into multiple objects: %w", err)
+		return common.NewValidationError(fmt.Errorf("error splitting YAML file into multiple objects: %w", err))
 	}
 
 	for _, yaml_object := range yamls {
 
 		schedulerJsonBytes, err := k8s_yaml.YAMLToJSON(yaml_object)
 		if err != nil {
-			return fmt.Errorf("error parsing YAML to Json: %w", err)
+			return common.NewValidationError(fmt.Errorf("error parsing YAML to Json: %w", err))
 		}
 
 		var request v1.CreateSchedulerRequest
 		err = protojson.Unmarshal(schedulerJsonBytes, &request)
 		if err != nil {
-			return fmt.Errorf("error parsing Json to v1.CreateSchedulerRequest: %w", err)
-		}
-
-		serializedRequest, err := common.Marshaller.Marshal(&request)
-		if err != nil {
-			return fmt.Errorf("error parsing request to json: %w", err)
+			return common.NewValidationError(fmt.Errorf("error parsing Json to v1.CreateSchedulerRequest: %w", err))
 		}
 
 		logger.Debug("creating scheduler: " + request.Name)
 
-		url := fmt.Sprintf("%s/schedulers", cs.config.ServerURL)
-		body, status, err := cs.client.Post(url, string(serializedRequest))
+		_, err = cs.client.CreateScheduler(ctx, &request)
 		if err != nil {
-			return fmt.Errorf("error on post request: %w", err)
-		}
-		if status != http.StatusOK {
-			return fmt.Errorf("create scheduler response not ok, status: %s, body: %s", http.StatusText(status), string(body))
+			return err
 		}
 
 		logger.Info("Successfully created scheduler: " + request.Name)
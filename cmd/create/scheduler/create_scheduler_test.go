@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -26,12 +27,12 @@ func TestCreateSchedulerAction(t *testing.T) {
 	client := mocks.NewMockClient(mockCtrl)
 
 	dirPath, _ := os.Getwd()
-	config := &extensions.Config{
+	config := &extensions.ContextConfig{
 		ServerURL: "http://localhost:8080",
 	}
 
 	t.Run("with success", func(t *testing.T) {
-		expectedStructuredBody := v1.CreateSchedulerRequest{
+		expectedStructuredBody := v1.Scheduler{
 			Name:     "scheduler-test",
 			Game:     "game-test",
 			MaxSurge: "10%",
@@ -68,24 +69,24 @@ func TestCreateSchedulerAction(t *testing.T) {
 			},
 		}
 
-		expectedStringBody, _ := protojson.Marshal(&expectedStructuredBody)
+		expectedStringBody, _ := protojson.Marshal(&v1.CreateSchedulerResponse{Scheduler: &expectedStructuredBody})
 
-		client.EXPECT().Post(config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(expectedStringBody), 200, nil)
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(expectedStringBody), 200, nil)
 
-		err := NewCreateScheduler(client, config).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
+		err := NewCreateScheduler(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
 
 		require.NoError(t, err)
 	})
 
 	t.Run("fails when no file found on path", func(t *testing.T) {
-		err := NewCreateScheduler(client, config).run(nil, []string{"fixtures/scheduler-config-not-found.yaml"})
+		err := NewCreateScheduler(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{"fixtures/scheduler-config-not-found.yaml"})
 
 		require.Error(t, err)
 		require.Equal(t, "error reading scheduler file: open fixtures/scheduler-config-not-found.yaml: no such file or directory", err.Error())
 	})
 
 	t.Run("fails when file found bad format", func(t *testing.T) {
-		err := NewCreateScheduler(client, config).run(nil, []string{dirPath + "/fixtures/scheduler-config-bad-format.yaml"})
+		err := NewCreateScheduler(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{dirPath + "/fixtures/scheduler-config-bad-format.yaml"})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error parsing Json to v1.CreateSchedulerRequest")
@@ -93,11 +94,11 @@ func TestCreateSchedulerAction(t *testing.T) {
 	})
 
 	t.Run("fails when maestro API fails", func(t *testing.T) {
-		client.EXPECT().Post(config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(""), 404, nil)
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(""), 404, nil)
 
-		err := NewCreateScheduler(client, config).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
+		err := NewCreateScheduler(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "create scheduler response not ok, status: Not Found, body: ")
+		require.Contains(t, err.Error(), "create scheduler failed with status Not Found")
 	})
 }
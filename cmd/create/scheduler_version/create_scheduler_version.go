@@ -1,15 +1,15 @@
 package scheduler_version
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 	"google.golang.org/protobuf/encoding/protojson"
 
@@ -28,19 +28,30 @@ var CreateSchedulerVersionCmd = &cobra.Command{
 			return err
 		}
 
-		return NewCreateSchedulerVersion(client, config).run(cmd, args)
+		err = common.Confirm(config, fmt.Sprintf("Create new scheduler version(s) from %s", args[0]))
+		if err != nil {
+			return err
+		}
+
+		return NewCreateSchedulerVersion(client, config, common.GetWaitParameters(cmd, config)).run(cmd, args)
 	},
 }
 
 type CreateSchedulerVersion struct {
-	client interfaces.Client
-	config *extensions.Config
+	client maestro.Client
+	config *extensions.ContextConfig
+	wait   *common.WaitParameters
+}
+
+func init() {
+	common.AddWaitFlags(CreateSchedulerVersionCmd)
 }
 
-func NewCreateSchedulerVersion(client interfaces.Client, config *extensions.Config) *CreateSchedulerVersion {
+func NewCreateSchedulerVersion(client maestro.Client, config *extensions.ContextConfig, wait *common.WaitParameters) *CreateSchedulerVersion {
 	return &CreateSchedulerVersion{
 		client: client,
 		config: config,
+		wait:   wait,
 	}
 }
 
@@ -58,7 +69,8 @@ func validateArgs(_ *cobra.Command, args []string) error {
 	return nil
 }
 
-func (cs *CreateSchedulerVersion) run(_ *cobra.Command, args []string) error {
+func (cs *CreateSchedulerVersion) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
 	logger := common.GetLogger()
 	filePath := args[0]
 	bts, err := ioutil.ReadFile(filePath)
@@ -66,59 +78,57 @@ func (cs *CreateSchedulerVersion) run(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("error reading scheduler version file: %w", err)
 	}
 	if isYaml := common.IsYAML(filePath); !isYaml {
-		return fmt.Errorf("file should be .yaml")
+		return common.NewValidationError(errors.New("file should be .yaml"))
 	}
 
 	yamls, err := common.SplitYAML(bts)
 	if err != nil {
-		return fmt.Errorf("error splitting YAML file into multiple objects: %w", err)
+		return common.NewValidationError(fmt.Errorf("error splitting YAML file into multiple objects: %w", err))
 	}
 
 	for _, yaml_object := range yamls {
 		schedulerJsonBytes, err := k8s_yaml.YAMLToJSON(yaml_object)
 		if err != nil {
-			return fmt.Errorf("error parsing YAML to Json: %w", err)
+			return common.NewValidationError(fmt.Errorf("error parsing YAML to Json: %w", err))
 		}
 
-		operationId, err := cs.EnqueueNewSchedulerVersionOperation(schedulerJsonBytes)
+		var request v1.NewSchedulerVersionRequest
+		err = protojson.Unmarshal(schedulerJsonBytes, &request)
+		if err != nil {
+			return common.NewValidationError(fmt.Errorf("error parsing Json to v1.NewSchedulerVersionRequest: %w", err))
+		}
+
+		operationId, err := cs.enqueue(ctx, &request)
 		if err != nil {
 			return err
 		}
 		logger.Info("Successfully executed new scheduler version. Operation id: " + operationId)
+
+		err = common.WaitOperation(ctx, cs.client, cs.wait, request.Name, operationId)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (cs *CreateSchedulerVersion) EnqueueNewSchedulerVersionOperation(schedulerJsonBytes []byte) (string, error) {
-	logger := common.GetLogger()
+func (cs *CreateSchedulerVersion) EnqueueNewSchedulerVersionOperation(ctx context.Context, schedulerJsonBytes []byte) (string, error) {
 	var request v1.NewSchedulerVersionRequest
 	err := protojson.Unmarshal(schedulerJsonBytes, &request)
 	if err != nil {
-		return "", fmt.Errorf("error parsing Json to v1.NewSchedulerVersionRequest: %w", err)
-	}
-
-	serializedRequest, err := common.Marshaller.Marshal(&request)
-	if err != nil {
-		return "", fmt.Errorf("error parsing request to json: %w", err)
+		return "", common.NewValidationError(fmt.Errorf("error parsing Json to v1.NewSchedulerVersionRequest: %w", err))
 	}
+	return cs.enqueue(ctx, &request)
+}
 
+func (cs *CreateSchedulerVersion) enqueue(ctx context.Context, request *v1.NewSchedulerVersionRequest) (string, error) {
+	logger := common.GetLogger()
 	logger.Debug("updating scheduler: " + request.Name)
 
-	url := fmt.Sprintf("%s/schedulers/%s", cs.config.ServerURL, request.Name)
-
-	body, status, err := cs.client.Post(url, string(serializedRequest))
-	if err != nil {
-		return "", fmt.Errorf("error on Post request: %w", err)
-	}
-	if status != http.StatusOK {
-		return "", fmt.Errorf("new scheduler version response not ok, status: %s, body: %s", http.StatusText(status), string(body))
-	}
-
-	var response v1.NewSchedulerVersionResponse
-	err = protojson.Unmarshal(body, &response)
+	response, err := cs.client.NewSchedulerVersion(ctx, request)
 	if err != nil {
-		return "", fmt.Errorf("error deserializing new scheduler version response, details: %w", err)
+		return "", err
 	}
 	return response.OperationId, nil
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -20,7 +21,7 @@ func TestCreateSchedulerVersionAction(t *testing.T) {
 	client := mocks.NewMockClient(mockCtrl)
 
 	dirPath, _ := os.Getwd()
-	config := &extensions.Config{
+	config := &extensions.ContextConfig{
 		ServerURL: "http://localhost:8080",
 	}
 
@@ -31,10 +32,10 @@ func TestCreateSchedulerVersionAction(t *testing.T) {
 		}
 		expectedStringBody, _ := protojson.Marshal(&expectedStructuredBody)
 		schedulerName := "scheduler-name-1"
-		client.EXPECT().Post(config.ServerURL+"/schedulers/"+schedulerName, gomock.Any()).Return([]byte(expectedStringBody), 200, nil)
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/"+schedulerName, gomock.Any()).Return([]byte(expectedStringBody), 200, nil)
 
 		// act
-		err := NewCreateSchedulerVersion(client, config).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
+		err := NewCreateSchedulerVersion(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
 
 		// assert
 		require.NoError(t, err)
@@ -42,7 +43,7 @@ func TestCreateSchedulerVersionAction(t *testing.T) {
 
 	t.Run("fails when no file found on path", func(t *testing.T) {
 		// act
-		err := NewCreateSchedulerVersion(client, config).run(nil, []string{"fixtures/scheduler-config-not-found.yaml"})
+		err := NewCreateSchedulerVersion(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"fixtures/scheduler-config-not-found.yaml"})
 
 		// assert
 		require.Error(t, err)
@@ -51,7 +52,7 @@ func TestCreateSchedulerVersionAction(t *testing.T) {
 
 	t.Run("fails when file found bad format", func(t *testing.T) {
 		// act
-		err := NewCreateSchedulerVersion(client, config).run(nil, []string{dirPath + "/fixtures/scheduler-config-bad-format.yaml"})
+		err := NewCreateSchedulerVersion(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{dirPath + "/fixtures/scheduler-config-bad-format.yaml"})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error parsing Json to v1.NewSchedulerVersionRequest")
@@ -59,7 +60,7 @@ func TestCreateSchedulerVersionAction(t *testing.T) {
 	})
 
 	t.Run("fails when file is not .yaml", func(t *testing.T) {
-		err := NewCreateSchedulerVersion(client, config).run(nil, []string{dirPath + "/fixtures/file_not_yaml.json"})
+		err := NewCreateSchedulerVersion(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{dirPath + "/fixtures/file_not_yaml.json"})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "file should be .yaml")
@@ -68,26 +69,26 @@ func TestCreateSchedulerVersionAction(t *testing.T) {
 	t.Run("fails when maestro API fails", func(t *testing.T) {
 		// arrange
 		schedulerName := "scheduler-name-1"
-		client.EXPECT().Post(config.ServerURL+"/schedulers/"+schedulerName, gomock.Any()).Return([]byte(""), 404, nil)
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/"+schedulerName, gomock.Any()).Return([]byte(""), 404, nil)
 
 		// act
-		err := NewCreateSchedulerVersion(client, config).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
+		err := NewCreateSchedulerVersion(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
 
 		// assert
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "new scheduler version response not ok, status: Not Found, body: ")
+		require.Contains(t, err.Error(), "new scheduler version failed with status Not Found")
 	})
 
 	t.Run("fails when got error on calling maestro API", func(t *testing.T) {
 		// arrange
 		schedulerName := "scheduler-name-1"
-		client.EXPECT().Post(config.ServerURL+"/schedulers/"+schedulerName, gomock.Any()).Return([]byte(""), 0, errors.New("error on API call"))
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/"+schedulerName, gomock.Any()).Return([]byte(""), 0, errors.New("error on API call"))
 
 		// act
-		err := NewCreateSchedulerVersion(client, config).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
+		err := NewCreateSchedulerVersion(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{dirPath + "/fixtures/scheduler-config.yaml"})
 
 		// assert
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "error on Post request: ")
+		require.Contains(t, err.Error(), "error on POST request: ")
 	})
 }
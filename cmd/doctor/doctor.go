@@ -0,0 +1,485 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	statusPass = "PASS"
+	statusFail = "FAIL"
+	statusSkip = "SKIP"
+
+	maestroModule  = "github.com/topfreegames/maestro"
+	dialTimeout    = 5 * time.Second
+	requestTimeout = 30 * time.Second
+	// maxClockSkew tolerates the one second resolution of the Date header and
+	// the request latency
+	maxClockSkew = 5 * time.Second
+)
+
+// Cmd represents the doctor command
+var Cmd = &cobra.Command{
+	Use:     "doctor",
+	Short:   "Diagnoses the connection to maestro",
+	Example: "maestro-cli doctor",
+	Long:    "Checks DNS resolution, TCP reachability, TLS handshake, auth, the maestro API, clock skew and API compatibility of the active context, showing how to fix every failed check.",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fs := extensions.NewFileSystem()
+		resolved, err := common.ResolveConfig(fs)
+		if err != nil {
+			return fmt.Errorf("error getting client config: %w", err)
+		}
+		// the other commands only check the context they use
+		config, err := extensions.ReadConfig(fs)
+		if err == nil {
+			err = config.Check()
+		}
+		if err != nil {
+			return err
+		}
+
+		tokenSource, err := common.GetTokenSource(resolved.ContextName, resolved.Context)
+		if err != nil {
+			return fmt.Errorf("error getting client: %w", err)
+		}
+
+		return NewDoctor(resolved.Context, tokenSource, os.Stdout).run(cmd, args)
+	},
+}
+
+type Doctor struct {
+	config      *extensions.ContextConfig
+	tokenSource extensions.TokenSource
+	out         io.Writer
+
+	ctx       context.Context
+	serverURL *url.URL
+	// socket is the unix socket of the server, empty for TCP servers
+	socket string
+	// proxy is dialed instead of the server when set
+	proxy *url.URL
+	// filled by the API check and used by the checks after it, response
+	// on the grpc transport and body on the http one
+	response     *v1.ListSchedulersResponse
+	body         []byte
+	date         string
+	requestStart time.Time
+	requestEnd   time.Time
+}
+
+func NewDoctor(config *extensions.ContextConfig, tokenSource extensions.TokenSource, out io.Writer) *Doctor {
+	return &Doctor{
+		config:      config,
+		tokenSource: tokenSource,
+		out:         out,
+	}
+}
+
+type result struct {
+	status string
+	detail string
+	hint   string
+}
+
+type check struct {
+	name string
+	run  func() result
+}
+
+func (d *Doctor) run(cmd *cobra.Command, _ []string) error {
+	d.ctx = common.CommandContext(cmd)
+	address, err := extensions.ParseServerURL(d.config.ServerURL)
+	if err != nil {
+		return fmt.Errorf("error parsing server url: %w", err)
+	}
+	serverURL, err := url.Parse(address.BaseURL)
+	if err != nil {
+		return fmt.Errorf("error parsing server url: %w", err)
+	}
+	d.serverURL = serverURL
+	d.socket = address.Socket
+	d.proxy, err = d.config.ProxyURL()
+	if err != nil {
+		return fmt.Errorf("error parsing proxy url: %w", err)
+	}
+
+	checks := []check{
+		{name: "DNS", run: d.checkDNS},
+		{name: "TCP", run: d.checkTCP},
+		{name: "TLS handshake", run: d.checkTLS},
+		{name: "Auth", run: d.checkAuth},
+		{name: "API", run: d.checkAPI},
+		{name: "Clock skew", run: d.checkClockSkew},
+		{name: "API compatibility", run: d.checkCompatibility},
+	}
+
+	w := new(tabwriter.Writer)
+	// minwidth, tabwidth, padding, padchar, flags
+	w.Init(d.out, 8, 8, 2, ' ', 0)
+
+	failed := 0
+	for _, c := range checks {
+		// every check depends on the previous ones passing
+		r := result{status: statusSkip, detail: "fix the failed check first"}
+		if failed == 0 {
+			r = c.run()
+		}
+		if r.status == statusFail {
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.status, c.name, r.detail)
+		if r.hint != "" {
+			fmt.Fprintf(w, "\t\thint: %s\n", r.hint)
+		}
+	}
+	w.Flush()
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// dialURL is the URL of the server, or of the proxy the requests are sent
+// through
+func (d *Doctor) dialURL() *url.URL {
+	if d.proxy != nil {
+		return d.proxy
+	}
+	return d.serverURL
+}
+
+func (d *Doctor) address() string {
+	dialURL := d.dialURL()
+	port := dialURL.Port()
+	if port == "" {
+		switch dialURL.Scheme {
+		case "https":
+			port = "443"
+		case "socks5":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(dialURL.Hostname(), port)
+}
+
+func (d *Doctor) checkDNS() result {
+	if d.socket != "" {
+		return result{status: statusSkip, detail: "server is reached through the unix socket " + d.socket}
+	}
+	host := d.dialURL().Hostname()
+	if net.ParseIP(host) != nil {
+		return result{status: statusPass, detail: host + " is an IP address"}
+	}
+
+	addrs, err := net.DefaultResolver.LookupHost(d.ctx, host)
+	if err != nil {
+		return result{
+			status: statusFail,
+			detail: err.Error(),
+			hint:   "check the serverUrl host name and your DNS or VPN settings",
+		}
+	}
+	return result{status: statusPass, detail: fmt.Sprintf("%s resolves to %s", host, strings.Join(addrs, ", "))}
+}
+
+func (d *Doctor) checkTCP() result {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if d.socket != "" {
+		conn, err := dialer.DialContext(d.ctx, "unix", d.socket)
+		if err != nil {
+			return result{
+				status: statusFail,
+				detail: err.Error(),
+				hint:   "check the tunnel or proxy exposing " + d.socket + " is running",
+			}
+		}
+		conn.Close()
+		return result{status: statusPass, detail: "connected to " + d.socket}
+	}
+
+	conn, err := dialer.DialContext(d.ctx, "tcp", d.address())
+	if err != nil {
+		return result{
+			status: statusFail,
+			detail: err.Error(),
+			hint:   "check the server is running and no firewall blocks " + d.address(),
+		}
+	}
+	conn.Close()
+	return result{status: statusPass, detail: "connected to " + d.address()}
+}
+
+func (d *Doctor) checkTLS() result {
+	if d.serverURL.Scheme != "https" {
+		return result{status: statusSkip, detail: "server uses plain HTTP"}
+	}
+	if d.proxy != nil {
+		return result{status: statusSkip, detail: "server is reached through the proxy " + d.proxy.Host + ", the API check covers TLS"}
+	}
+
+	tlsConfig, err := extensions.NewTLSConfig(d.config)
+	if err != nil {
+		return result{
+			status: statusFail,
+			detail: err.Error(),
+			hint:   "fix the caFile, certFile and keyFile of the context",
+		}
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = d.serverURL.Hostname()
+	}
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: dialTimeout}, Config: tlsConfig}
+	conn, err := dialer.DialContext(d.ctx, "tcp", d.address())
+	if err != nil {
+		return result{
+			status: statusFail,
+			detail: err.Error(),
+			hint:   "set caFile to the CA that signed the server certificate, or serverName to the name it was issued to",
+		}
+	}
+	defer conn.Close()
+
+	if d.config.InsecureSkipVerify {
+		return result{
+			status: statusPass,
+			detail: "certificate not verified",
+			hint:   "insecureSkipVerify is set, remove it and set caFile instead",
+		}
+	}
+	certificate := conn.(*tls.Conn).ConnectionState().PeerCertificates[0]
+	return result{status: statusPass, detail: "certificate valid until " + certificate.NotAfter.Format(time.RFC3339)}
+}
+
+func (d *Doctor) checkAuth() result {
+	if d.tokenSource == nil {
+		return result{status: statusSkip, detail: "no auth configured"}
+	}
+
+	token, err := d.tokenSource.Token(d.ctx)
+	if errors.Is(err, extensions.ErrLoginRequired) {
+		return result{status: statusFail, detail: err.Error(), hint: "run maestro-cli login"}
+	}
+	if err != nil {
+		return result{status: statusFail, detail: err.Error(), hint: "check the auth settings of the context"}
+	}
+	if token.Expiry.IsZero() {
+		return result{status: statusPass, detail: "token available"}
+	}
+	return result{status: statusPass, detail: "token valid until " + token.Expiry.Format(time.RFC3339)}
+}
+
+func (d *Doctor) checkAPI() result {
+	if d.config.GetTransport() == extensions.TransportGRPC {
+		return d.checkGRPCAPI()
+	}
+
+	client, err := extensions.NewClient(d.config, d.tokenSource)
+	if err != nil {
+		return result{status: statusFail, detail: err.Error(), hint: "fix the context settings"}
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", d.config.BaseURL()+"/schedulers", nil)
+	if err != nil {
+		return result{status: statusFail, detail: err.Error(), hint: "fix the context serverUrl"}
+	}
+
+	d.requestStart = time.Now()
+	res, err := client.Do(req)
+	if err != nil {
+		return result{status: statusFail, detail: err.Error(), hint: "check the serverUrl and the maestro logs"}
+	}
+	defer res.Body.Close()
+	d.body, err = ioutil.ReadAll(res.Body)
+	d.requestEnd = time.Now()
+	if err != nil {
+		return result{status: statusFail, detail: err.Error(), hint: "check the serverUrl and the maestro logs"}
+	}
+	d.date = res.Header.Get("Date")
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		return result{
+			status: statusFail,
+			detail: "GET /schedulers responded " + http.StatusText(res.StatusCode),
+			hint:   "maestro rejected the credentials, run maestro-cli login or check the auth settings of the context",
+		}
+	case res.StatusCode != http.StatusOK:
+		return result{
+			status: statusFail,
+			detail: "GET /schedulers responded " + http.StatusText(res.StatusCode),
+			hint:   "check the serverUrl points to the maestro API",
+		}
+	}
+	latency := d.requestEnd.Sub(d.requestStart).Round(time.Millisecond)
+	return result{status: statusPass, detail: fmt.Sprintf("GET /schedulers responded in %s", latency)}
+}
+
+// checkGRPCAPI calls the ListSchedulers method of the maestro gRPC services
+func (d *Doctor) checkGRPCAPI() result {
+	conn, err := extensions.NewGRPCConn(d.config, d.tokenSource)
+	if err != nil {
+		return result{status: statusFail, detail: err.Error(), hint: "fix the context settings"}
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(d.ctx, requestTimeout)
+	defer cancel()
+	d.requestStart = time.Now()
+	d.response, err = maestro.NewGRPCClient(conn).ListSchedulers(ctx, &v1.ListSchedulersRequest{})
+	d.requestEnd = time.Now()
+	var apiErr *maestro.APIError
+	switch {
+	case errors.As(err, &apiErr) && (apiErr.Code == codes.Unauthenticated || apiErr.Code == codes.PermissionDenied):
+		return result{
+			status: statusFail,
+			detail: "ListSchedulers responded " + apiErr.Code.String(),
+			hint:   "maestro rejected the credentials, run maestro-cli login or check the auth settings of the context",
+		}
+	case errors.As(err, &apiErr):
+		return result{
+			status: statusFail,
+			detail: fmt.Sprintf("ListSchedulers responded %s: %s", apiErr.Code, apiErr.Message),
+			hint:   "check the serverUrl points to the maestro gRPC services, or remove transport: grpc to call the HTTP gateway",
+		}
+	case err != nil:
+		return result{status: statusFail, detail: err.Error(), hint: "check the serverUrl and the maestro logs"}
+	}
+	latency := d.requestEnd.Sub(d.requestStart).Round(time.Millisecond)
+	return result{status: statusPass, detail: fmt.Sprintf("ListSchedulers responded in %s", latency)}
+}
+
+func (d *Doctor) checkClockSkew() result {
+	if d.config.GetTransport() == extensions.TransportGRPC {
+		return result{status: statusSkip, detail: "the gRPC transport sends no server time"}
+	}
+	if d.date == "" {
+		return result{status: statusSkip, detail: "server sent no Date header"}
+	}
+	serverTime, err := http.ParseTime(d.date)
+	if err != nil {
+		return result{status: statusSkip, detail: fmt.Sprintf("bad Date header %q", d.date)}
+	}
+
+	localTime := d.requestStart.Add(d.requestEnd.Sub(d.requestStart) / 2)
+	skew := localTime.Sub(serverTime).Round(time.Second)
+	if skew > maxClockSkew || skew < -maxClockSkew {
+		return result{
+			status: statusFail,
+			detail: fmt.Sprintf("local clock is %s off the server clock", skew),
+			hint:   "sync the local clock with NTP, LEASE_EXPIRED of get operations is computed from it",
+		}
+	}
+	return result{status: statusPass, detail: fmt.Sprintf("local clock is %s off the server clock", skew)}
+}
+
+func (d *Doctor) checkCompatibility() result {
+	apiVersion := maestroAPIVersion()
+	if d.response != nil {
+		if hasUnknownFields(d.response.ProtoReflect()) {
+			return result{
+				status: statusPass,
+				detail: "responses have fields unknown to " + apiVersion,
+				hint:   "upgrade maestro-cli to see the fields added by the server",
+			}
+		}
+		return result{status: statusPass, detail: "responses match " + apiVersion}
+	}
+
+	var response v1.ListSchedulersResponse
+	err := maestro.Decode(d.body, &response)
+	if err != nil {
+		return result{
+			status: statusFail,
+			detail: fmt.Sprintf("responses do not match %s: %s", apiVersion, err),
+			hint:   "upgrade maestro-cli to a version built with the maestro API the server runs",
+		}
+	}
+	// unknown fields are discarded by the commands, the server is newer
+	err = protojson.Unmarshal(d.body, &response)
+	if err != nil {
+		return result{
+			status: statusPass,
+			detail: fmt.Sprintf("responses have fields unknown to %s: %s", apiVersion, err),
+			hint:   "upgrade maestro-cli to see the fields added by the server",
+		}
+	}
+	return result{status: statusPass, detail: "responses match " + apiVersion}
+}
+
+// hasUnknownFields returns true when m or the messages it holds have fields
+// unknown to the API, kept by the gRPC decoding
+func hasUnknownFields(m protoreflect.Message) bool {
+	if len(m.GetUnknown()) > 0 {
+		return true
+	}
+	found := false
+	m.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsList() && field.Message() != nil:
+			for i := 0; i < value.List().Len() && !found; i++ {
+				found = hasUnknownFields(value.List().Get(i).Message())
+			}
+		case field.IsMap() && field.MapValue().Message() != nil:
+			value.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				found = hasUnknownFields(v.Message())
+				return !found
+			})
+		case !field.IsList() && !field.IsMap() && field.Message() != nil:
+			found = hasUnknownFields(value.Message())
+		}
+		return !found
+	})
+	return found
+}
+
+// maestroAPIVersion returns the maestro module the API messages come from
+func maestroAPIVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if ok {
+		for _, dep := range info.Deps {
+			if dep.Path == maestroModule {
+				return maestroModule + " " + dep.Version
+			}
+		}
+	}
+	return maestroModule
+}
@@ -0,0 +1,243 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/extensions"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// schedulersServer responds to ListSchedulers with response, or fails with
+// Unauthenticated when the call is not authorized with the-token
+type schedulersServer struct {
+	v1.UnimplementedSchedulersServiceServer
+	response *v1.ListSchedulersResponse
+}
+
+func (s *schedulersServer) ListSchedulers(ctx context.Context, _ *v1.ListSchedulersRequest) (*v1.ListSchedulersResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if authorization := md.Get("authorization"); len(authorization) != 1 || authorization[0] != "Bearer the-token" {
+		return nil, status.Error(codes.Unauthenticated, "bad token")
+	}
+	return s.response, nil
+}
+
+// newGRPCServer serves the schedulers service on a local TCP port, returning
+// its http URL
+func newGRPCServer(t *testing.T, response *v1.ListSchedulersResponse) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	v1.RegisterSchedulersServiceServer(server, &schedulersServer{response: response})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return "http://" + listener.Addr().String()
+}
+
+func TestDoctorAction(t *testing.T) {
+	handler := func(status int, body string, date time.Time) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/schedulers", r.URL.Path)
+			require.Equal(t, "Bearer the-token", r.Header.Get("Authorization"))
+			w.Header().Set("Date", date.UTC().Format(http.TimeFormat))
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+		}
+	}
+	// the TCP check closes the connection before the TLS handshake, which the
+	// server logs
+	newTLSServer := func(handler http.Handler) *httptest.Server {
+		server := httptest.NewUnstartedServer(handler)
+		server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+		server.StartTLS()
+		return server
+	}
+	tokenSource, err := extensions.NewTokenSource("test", &extensions.ContextConfig{Auth: &extensions.AuthConfig{Token: "the-token"}}, nil)
+	require.NoError(t, err)
+
+	t.Run("with success", func(t *testing.T) {
+		server := httptest.NewServer(handler(http.StatusOK, `{"schedulers":[{"name":"scheduler","game":"game"}]}`, time.Now()))
+		defer server.Close()
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: server.URL}, tokenSource, out).run(nil, nil)
+
+		require.NoError(t, err)
+		require.Regexp(t, `PASS +DNS +127.0.0.1 is an IP address\n`, out.String())
+		require.Regexp(t, `PASS +TCP +connected to 127.0.0.1:\d+\n`, out.String())
+		require.Regexp(t, `SKIP +TLS handshake +server uses plain HTTP\n`, out.String())
+		require.Regexp(t, `PASS +Auth +token available\n`, out.String())
+		require.Regexp(t, `PASS +API +GET /schedulers responded in`, out.String())
+		require.Regexp(t, `PASS +Clock skew +local clock is -?[01]s off the server clock\n`, out.String())
+		require.Regexp(t, `PASS +API compatibility +responses match github.com/topfreegames/maestro`, out.String())
+	})
+
+	t.Run("checks servers reached through unix sockets", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "maestro")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		socket := filepath.Join(dir, "maestro.sock")
+		listener, err := net.Listen("unix", socket)
+		require.NoError(t, err)
+		server := httptest.NewUnstartedServer(handler(http.StatusOK, `{"schedulers":[]}`, time.Now()))
+		server.Listener = listener
+		server.Start()
+		defer server.Close()
+
+		out := new(bytes.Buffer)
+		err = NewDoctor(&extensions.ContextConfig{ServerURL: "unix://" + socket}, tokenSource, out).run(nil, nil)
+
+		require.NoError(t, err)
+		require.Regexp(t, `SKIP +DNS +server is reached through the unix socket `, out.String())
+		require.Regexp(t, `PASS +TCP +connected to .*maestro.sock\n`, out.String())
+		require.Regexp(t, `PASS +API +GET /schedulers responded in`, out.String())
+	})
+
+	t.Run("verifies the server certificate with the context CA", func(t *testing.T) {
+		server := newTLSServer(handler(http.StatusOK, `{}`, time.Now()))
+		defer server.Close()
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		require.NoError(t, ioutil.WriteFile(caFile, caPEM, 0600))
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: server.URL, CAFile: caFile}, tokenSource, out).run(nil, nil)
+
+		require.NoError(t, err)
+		require.Regexp(t, `PASS +TLS handshake +certificate valid until`, out.String())
+	})
+
+	t.Run("fails when the server certificate is not trusted", func(t *testing.T) {
+		server := newTLSServer(handler(http.StatusOK, `{}`, time.Now()))
+		defer server.Close()
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: server.URL}, tokenSource, out).run(nil, nil)
+
+		require.Error(t, err)
+		require.Equal(t, "1 check(s) failed", err.Error())
+		require.Regexp(t, `FAIL +TLS handshake +.*certificate`, out.String())
+		require.Contains(t, out.String(), "hint: set caFile to the CA that signed the server certificate")
+		require.Regexp(t, `SKIP +API +fix the failed check first\n`, out.String())
+	})
+
+	t.Run("fails when the server is not reachable", func(t *testing.T) {
+		server := httptest.NewServer(handler(http.StatusOK, `{}`, time.Now()))
+		server.Close()
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: server.URL}, tokenSource, out).run(nil, nil)
+
+		require.Error(t, err)
+		require.Regexp(t, `FAIL +TCP +.*connection refused`, out.String())
+		require.Contains(t, out.String(), "hint: check the server is running and no firewall blocks")
+	})
+
+	t.Run("fails when maestro rejects the credentials", func(t *testing.T) {
+		server := httptest.NewServer(handler(http.StatusUnauthorized, `{}`, time.Now()))
+		defer server.Close()
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: server.URL}, tokenSource, out).run(nil, nil)
+
+		require.Error(t, err)
+		require.Regexp(t, `FAIL +API +GET /schedulers responded Unauthorized\n`, out.String())
+		require.Contains(t, out.String(), "hint: maestro rejected the credentials, run maestro-cli login")
+	})
+
+	t.Run("fails when the local clock is off", func(t *testing.T) {
+		server := httptest.NewServer(handler(http.StatusOK, `{}`, time.Now().Add(-time.Hour)))
+		defer server.Close()
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: server.URL}, tokenSource, out).run(nil, nil)
+
+		require.Error(t, err)
+		require.Regexp(t, `FAIL +Clock skew +local clock is 1h0m[01]s off the server clock\n`, out.String())
+		require.Contains(t, out.String(), "hint: sync the local clock with NTP")
+	})
+
+	t.Run("warns when responses have fields unknown to the maestro API", func(t *testing.T) {
+		server := httptest.NewServer(handler(http.StatusOK, `{"schedulers":[{"name":"scheduler","newField":"value"}]}`, time.Now()))
+		defer server.Close()
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: server.URL}, tokenSource, out).run(nil, nil)
+
+		require.NoError(t, err)
+		require.Regexp(t, `PASS +API compatibility +responses have fields unknown to github.com/topfreegames/maestro`, out.String())
+		require.Contains(t, out.String(), "hint: upgrade maestro-cli to see the fields added by the server")
+	})
+
+	t.Run("fails when responses do not match the maestro API", func(t *testing.T) {
+		server := httptest.NewServer(handler(http.StatusOK, `{"schedulers":"scheduler"}`, time.Now()))
+		defer server.Close()
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: server.URL}, tokenSource, out).run(nil, nil)
+
+		require.Error(t, err)
+		require.Regexp(t, `FAIL +API compatibility +responses do not match github.com/topfreegames/maestro`, out.String())
+		require.Contains(t, out.String(), "hint: upgrade maestro-cli")
+	})
+
+	t.Run("checks the gRPC services on the grpc transport", func(t *testing.T) {
+		serverURL := newGRPCServer(t, &v1.ListSchedulersResponse{Schedulers: []*v1.SchedulerWithoutSpec{{Name: "scheduler"}}})
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: serverURL, Transport: extensions.TransportGRPC}, tokenSource, out).run(nil, nil)
+
+		require.NoError(t, err)
+		require.Regexp(t, `PASS +API +ListSchedulers responded in`, out.String())
+		require.Regexp(t, `SKIP +Clock skew +the gRPC transport sends no server time\n`, out.String())
+		require.Regexp(t, `PASS +API compatibility +responses match github.com/topfreegames/maestro`, out.String())
+	})
+
+	t.Run("warns when gRPC responses have fields unknown to the maestro API", func(t *testing.T) {
+		scheduler := &v1.SchedulerWithoutSpec{Name: "scheduler"}
+		scheduler.ProtoReflect().SetUnknown(protowire.AppendString(protowire.AppendTag(nil, 999, protowire.BytesType), "value"))
+		serverURL := newGRPCServer(t, &v1.ListSchedulersResponse{Schedulers: []*v1.SchedulerWithoutSpec{scheduler}})
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: serverURL, Transport: extensions.TransportGRPC}, tokenSource, out).run(nil, nil)
+
+		require.NoError(t, err)
+		require.Regexp(t, `PASS +API compatibility +responses have fields unknown to github.com/topfreegames/maestro`, out.String())
+	})
+
+	t.Run("fails when the gRPC services reject the credentials", func(t *testing.T) {
+		serverURL := newGRPCServer(t, &v1.ListSchedulersResponse{})
+
+		out := new(bytes.Buffer)
+		err := NewDoctor(&extensions.ContextConfig{ServerURL: serverURL, Transport: extensions.TransportGRPC}, nil, out).run(nil, nil)
+
+		require.Error(t, err)
+		require.Regexp(t, `FAIL +API +ListSchedulers responded Unauthenticated\n`, out.String())
+		require.Contains(t, out.String(), "hint: maestro rejected the credentials")
+	})
+}
@@ -8,7 +8,19 @@
 package get
 
 import (
+	"io"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/printer"
+)
+
+var (
+	output string
+	// tableOptions are set by the flags of the table formats
+	tableOptions printer.TableOptions
 )
 
 var Cmd = &cobra.Command{
@@ -18,8 +30,36 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	Cmd.PersistentFlags().StringVarP(&output, "output", "o", printer.Table, "Output format, one of "+strings.Join(printer.Formats, ", ")+", or "+strings.Join(printer.TemplateFormats, "|")+"=TEMPLATE evaluated against the JSON output. Defaults to the context defaults.output.")
+	Cmd.PersistentFlags().StringVar(&tableOptions.Columns, "columns", "", "Columns of the table output as comma separated NAME:JSONPATH pairs evaluated against every item, e.g. NAME:.name,STATE:.state")
+	Cmd.PersistentFlags().StringVar(&tableOptions.SortBy, "sort-by", "", "Sort the table and name outputs by a column, e.g. AGE, or a JSONPath of the items, e.g. .createdAt")
+	Cmd.PersistentFlags().BoolVar(&tableOptions.Reverse, "reverse", false, "Reverse the order of the table and name outputs")
+	Cmd.PersistentFlags().BoolVar(&tableOptions.NoHeaders, "no-headers", false, "Do not print the table headers")
+	Cmd.PersistentFlags().StringVar(&timeFormat, "time-format", timeRelative, "Format of the table times, one of "+strings.Join(timeFormats, ", "))
+	Cmd.PersistentFlags().StringVar(&timeZone, "tz", "", "Time zone of the table times, e.g. America/Sao_Paulo, defaults to UTC on rfc3339 and to the local zone on local")
+
 	Cmd.AddCommand(getSchedulersCmd)
+	Cmd.AddCommand(getSchedulerCmd)
 	Cmd.AddCommand(getOperationsCmd)
 	Cmd.AddCommand(getSchedulersInfoCmd)
 	Cmd.AddCommand(getOperationCmd)
 }
+
+// newPrinter returns the printer of --output, or of the context
+// defaults.output when the flag is not set, with the table options of the
+// flags checked against resource
+func newPrinter(cmd *cobra.Command, config *extensions.ContextConfig, out io.Writer, resource *printer.Resource) (*printer.Printer, error) {
+	format := output
+	if (cmd == nil || !cmd.Flags().Changed("output")) && config.GetDefaults().Output != "" {
+		format = config.GetDefaults().Output
+	}
+	p, err := printer.New(format, out)
+	if err != nil {
+		return nil, common.NewValidationError(err)
+	}
+	err = p.SetTableOptions(&tableOptions, resource)
+	if err != nil {
+		return nil, common.NewValidationError(err)
+	}
+	return p, nil
+}
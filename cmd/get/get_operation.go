@@ -9,17 +9,16 @@ package get
 
 import (
 	"errors"
-	"fmt"
-	"net/http"
+	"io"
 	"os"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"github.com/topfreegames/maestro-cli/printer"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
-	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 var includeOperationInput, includeOperationExecutionHistory bool
@@ -57,71 +56,56 @@ func validateGetOperationArgs(_ *cobra.Command, args []string) error {
 }
 
 type GetOperation struct {
-	client interfaces.Client
-	config *extensions.Config
+	client maestro.Client
+	config *extensions.ContextConfig
+	out    io.Writer
 }
 
-func NewGetOperation(client interfaces.Client, config *extensions.Config) *GetOperation {
+func NewGetOperation(client maestro.Client, config *extensions.ContextConfig) *GetOperation {
 	return &GetOperation{
 		client: client,
 		config: config,
+		out:    os.Stdout,
 	}
 }
 
-func (cs *GetOperation) runGetOperation(_ *cobra.Command, args []string) error {
+func (cs *GetOperation) runGetOperation(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
+	times, err := newTimeFormatter(timeFormat, timeZone)
+	if err != nil {
+		return err
+	}
+	columns := operationColumns(times)
+	if includeOperationInput {
+		columns = append(columns, printer.Column{Header: "INPUT", Field: ".input", Value: operationInput})
+	}
+	if includeOperationExecutionHistory {
+		columns = append(columns, printer.Column{Header: "EXEC. HIST.", Field: ".executionHistory", Value: operationExecutionHistory(times)})
+	}
+	resource := &printer.Resource{
+		Message: &v1.Operation{},
+		Columns: columns,
+		Name:    operationName,
+	}
+	p, err := newPrinter(cmd, cs.config, cs.out, resource)
+	if err != nil {
+		return err
+	}
 	logger := common.GetLogger()
 	logger.Debug("getting operation")
 
 	schedulerName := args[0]
 	operationID := args[1]
-	url := fmt.Sprintf("%s/schedulers/%s/operations/%s", cs.config.ServerURL, schedulerName, operationID)
-	body, status, err := cs.client.Get(url, "")
+	operationResponse, err := cs.client.GetOperation(ctx, &v1.GetOperationRequest{SchedulerName: schedulerName, OperationId: operationID})
 	if err != nil {
-		return fmt.Errorf("error on GET request: %w", err)
-	}
-
-	if status != http.StatusOK {
-		return fmt.Errorf("get operation response not ok, status: %s, body: %s", http.StatusText(status), string(body))
+		return err
 	}
 
-	logger.Sugar().Debugf("success getting operation %s: %s", body, operationID)
+	logger.Sugar().Debugf("success getting operation %s", operationID)
 
-	var operationResponse v1.GetOperationResponse
-	err = protojson.Unmarshal(body, &operationResponse)
-	if err != nil {
-		return fmt.Errorf("error parsing response body: %w", err)
+	var items []proto.Message
+	if operationResponse.GetOperation() != nil {
+		items = append(items, operationResponse.GetOperation())
 	}
-
-	cs.printOperation(operationResponse.Operation)
-	return nil
-}
-
-func (cs *GetOperation) printOperation(operation *v1.Operation) {
-	w := new(tabwriter.Writer)
-
-	// minwidth, tabwidth, padding, padchar, flags
-	w.Init(os.Stdout, 8, 8, 0, '\t', 0)
-
-	defer w.Flush()
-
-	defaultHeaders := []interface{}{"ID", "NAME", "STATUS", "AGE", "LEASE_TTL", "LEASE_EXPIRED"}
-	format := "%s\t\t%s\t\t%s\t\t%s\t\t%s\t\t%s"
-
-	headers := make([]interface{}, 0)
-	headers = append(headers, defaultHeaders...)
-
-	if includeOperationInput {
-		format = format + "\t\t%s"
-		headers = append(headers, "INPUT")
-	}
-	if includeOperationExecutionHistory {
-		format = format + "\t\t%s"
-		headers = append(headers, "EXEC. HIST.")
-	}
-
-	format = format + "\t\n"
-	fmt.Fprintf(w, format, headers...)
-
-	values := getOperationValues(operation, includeOperationInput, includeOperationExecutionHistory)
-	fmt.Fprintf(w, format, values...)
+	return p.Print(operationResponse, items, resource)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -139,22 +140,21 @@ func TestGetOperationAction(t *testing.T) {
 				statusCode:         400,
 				clientError:        nil,
 			},
-			errWanted: errors.New("get operation response not ok, status: Bad Request, body: {}"),
+			errWanted: errors.New("get operation failed with status Bad Request: {}"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
-			config := &extensions.Config{ServerURL: "http://localhost:8080"}
+			config := &extensions.ContextConfig{ServerURL: "http://localhost:8080"}
 			client := mocks.NewMockClient(mockCtrl)
 			responseBody, err := protojson.Marshal(tt.mockPreparation.operationRetrieved)
 			require.NoError(t, err)
-			client.EXPECT().
-				Get(fmt.Sprintf("%s/schedulers/%s/operations/%s", config.ServerURL, tt.input.schedulerName, tt.input.operationId), gomock.Any()).Return(responseBody, tt.mockPreparation.statusCode, tt.mockPreparation.clientError)
+			client.EXPECT().Get(gomock.Any(), fmt.Sprintf("%s/schedulers/%s/operations/%s", config.ServerURL, tt.input.schedulerName, tt.input.operationId), gomock.Any()).Return(responseBody, tt.mockPreparation.statusCode, tt.mockPreparation.clientError)
 			includeOperationInput = tt.input.includeInputFlag
 			includeOperationExecutionHistory = tt.input.includeHistoryFlag
 
-			err = NewGetOperation(client, config).runGetOperation(&cobra.Command{}, []string{tt.input.schedulerName, tt.input.operationId})
+			err = NewGetOperation(maestro.NewClient(client, config.ServerURL), config).runGetOperation(&cobra.Command{}, []string{tt.input.schedulerName, tt.input.operationId})
 
 			if tt.errWanted != nil {
 				require.EqualError(t, err, tt.errWanted.Error())
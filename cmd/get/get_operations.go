@@ -10,22 +10,21 @@ package get
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"net/http"
+	"io"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
-	"text/tabwriter"
 	"time"
 
-	"github.com/hako/durafmt"
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"github.com/topfreegames/maestro-cli/printer"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
-	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // getOperationsCmd represents the list command
@@ -53,142 +52,149 @@ func validateArgs(_ *cobra.Command, args []string) error {
 }
 
 type GetOperations struct {
-	client interfaces.Client
-	config *extensions.Config
+	client maestro.Client
+	config *extensions.ContextConfig
+	out    io.Writer
 }
 
-func NewGetOperations(client interfaces.Client, config *extensions.Config) *GetOperations {
+func NewGetOperations(client maestro.Client, config *extensions.ContextConfig) *GetOperations {
 	return &GetOperations{
 		client: client,
 		config: config,
+		out:    os.Stdout,
 	}
 }
 
-func (cs *GetOperations) run(_ *cobra.Command, args []string) error {
+func (cs *GetOperations) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
+	times, err := newTimeFormatter(timeFormat, timeZone)
+	if err != nil {
+		return err
+	}
+	resource := &printer.Resource{
+		Message: &v1.Operation{},
+		Columns: operationColumns(times),
+		Name:    operationName,
+		Empty:   "no operations found",
+	}
+	p, err := newPrinter(cmd, cs.config, cs.out, resource)
+	if err != nil {
+		return err
+	}
 	logger := common.GetLogger()
 	logger.Debug("getting operations")
 
 	schedulerName := args[0]
-	url := fmt.Sprintf("%s/schedulers/%s/operations", cs.config.ServerURL, schedulerName)
-	body, status, err := cs.client.Get(url, "")
+	operationsLists, err := cs.client.ListOperations(ctx, &v1.ListOperationsRequest{SchedulerName: schedulerName})
 	if err != nil {
-		return fmt.Errorf("error on GET request: %w", err)
-	}
-
-	if status != http.StatusOK {
-		return fmt.Errorf("get operations response not ok, status: %s, body: %s", http.StatusText(status), string(body))
+		return err
 	}
 
-	logger.Sugar().Debugf("success getting scheduler operations: %s", body)
+	logger.Sugar().Debugf("success getting scheduler operations: %s", operationsLists)
 
-	var operationsLists v1.ListOperationsResponse
-	err = protojson.Unmarshal(body, &operationsLists)
-	if err != nil {
-		return fmt.Errorf("error parsing response body: %w", err)
-	}
-
-	// merge all operations into a single slice
+	// merge all operations into a single slice, copying the response lists
+	// as the response itself is printed by -o json
 	// TODO(gabriel.corado): add option to only show operations with specific
 	// status.
-	mergedOperations := append(operationsLists.GetPendingOperations(), operationsLists.GetActiveOperations()...)
+	mergedOperations := append([]*v1.Operation{}, operationsLists.GetPendingOperations()...)
+	mergedOperations = append(mergedOperations, operationsLists.GetActiveOperations()...)
 	mergedOperations = append(mergedOperations, operationsLists.GetFinishedOperations()...)
 
-	// TODO(gabriel.corado): add a option to reverse this order.
-	sort.Slice(mergedOperations, func(i, j int) bool {
+	// oldest first, --sort-by and --reverse change this order
+	sort.SliceStable(mergedOperations, func(i, j int) bool {
 		return mergedOperations[i].GetCreatedAt().AsTime().Before(mergedOperations[j].GetCreatedAt().AsTime())
 	})
 
-	if len(mergedOperations) == 0 {
-		fmt.Println("no operations found")
-		return nil
+	items := make([]proto.Message, 0, len(mergedOperations))
+	for _, operation := range mergedOperations {
+		items = append(items, operation)
 	}
-
-	cs.printOperationsTable(mergedOperations)
-	return nil
+	return p.Print(operationsLists, items, resource)
 }
 
-// printOperationsTable receives the operations sorted and print the table to
-// stdout.
-func (cs *GetOperations) printOperationsTable(operations []*v1.Operation) {
-	w := new(tabwriter.Writer)
-
-	// minwidth, tabwidth, padding, padchar, flags
-	w.Init(os.Stdout, 8, 8, 0, '\t', 0)
-
-	defer w.Flush()
-
-	defaultHeaders := []interface{}{"ID", "NAME", "STATUS", "AGE", "LEASE_TTL", "LEASE_EXPIRED"}
-	format := "%s\t\t%s\t\t%s\t\t%s\t\t%s\t\t%s"
-
-	headers := make([]interface{}, 0)
-	headers = append(headers, defaultHeaders...)
-
-	format = format + "\t\n"
-	fmt.Fprintf(w, format, headers...)
-
-	for _, operation := range operations {
-		values := getOperationValues(operation, false, false)
-		fmt.Fprintf(w, format, values...)
+// operationColumns are the columns of get operations and get operation, with
+// times printed by times
+func operationColumns(times *timeFormatter) []printer.Column {
+	createdAt := func(item proto.Message) *timestamppb.Timestamp { return item.(*v1.Operation).GetCreatedAt() }
+	leaseColor := func(item proto.Message) printer.Color {
+		if _, expired := getOperationLeaseInfo(item.(*v1.Operation), times); expired == "TRUE" {
+			return printer.Red
+		}
+		return printer.NoColor
 	}
+	columns := []printer.Column{
+		{Header: "ID", Field: ".id", Value: func(item proto.Message) string { return item.(*v1.Operation).GetId() }},
+		{Header: "NAME", Field: ".definitionName", Value: func(item proto.Message) string { return strings.ToUpper(item.(*v1.Operation).GetDefinitionName()) }},
+		{
+			Header: "STATUS",
+			Field:  ".status",
+			Value:  func(item proto.Message) string { return strings.ToUpper(item.(*v1.Operation).GetStatus()) },
+			Color:  func(item proto.Message) printer.Color { return operationStatusColor(item.(*v1.Operation).GetStatus()) },
+		},
+		times.ageColumn(createdAt),
+		{Header: "LEASE_TTL", Field: ".lease.ttl", Color: leaseColor, Value: func(item proto.Message) string {
+			leaseTtl, _ := getOperationLeaseInfo(item.(*v1.Operation), times)
+			return leaseTtl
+		}},
+		{Header: "LEASE_EXPIRED", Field: ".lease.ttl", Color: leaseColor, Value: func(item proto.Message) string {
+			_, leaseExpired := getOperationLeaseInfo(item.(*v1.Operation), times)
+			return leaseExpired
+		}},
+		{Header: "SCHEDULER", Wide: true, Field: ".schedulerName", Value: func(item proto.Message) string { return item.(*v1.Operation).GetSchedulerName() }},
+	}
+	return append(columns, times.createdAtColumns(createdAt)...)
 }
 
-func getOperationValues(operation *v1.Operation, includeInput, incluseHistory bool) []interface{} {
-	age := time.Since(operation.GetCreatedAt().AsTime())
-	prettyAge := durafmt.ParseShort(age).String()
-
-	leaseTtl, leaseExpired := getOperationLeaseInfo(operation)
+// operationStatusColor colors the operation statuses: finished, in progress
+// and failed
+func operationStatusColor(status string) printer.Color {
+	switch status {
+	case "finished":
+		return printer.Green
+	case "pending", "in_progress":
+		return printer.Yellow
+	case "error", "evicted":
+		return printer.Red
+	}
+	return printer.NoColor
+}
 
-	values := make([]interface{}, 0)
+func operationName(item proto.Message) string {
+	return item.(*v1.Operation).GetId()
+}
 
-	defaultValues := []interface{}{
-		operation.GetId(),
-		strings.ToUpper(operation.GetDefinitionName()),
-		strings.ToUpper(operation.GetStatus()),
-		prettyAge,
-		leaseTtl,
-		leaseExpired,
-	}
-	values = append(values, defaultValues...)
-	values = appendInputIfRequested(values, operation, includeInput)
-	values = appendExecutionHistoryValueIfRequested(values, operation, incluseHistory)
-	return values
+func operationInput(item proto.Message) string {
+	return fromFieldToJson(item.(*v1.Operation).GetInput())
 }
 
-func appendExecutionHistoryValueIfRequested(values []interface{}, operation *v1.Operation, includeHistory bool) []interface{} {
-	if includeHistory {
-		type printableEvent struct {
-			CreatedAt string `json:"createdAt"`
-			Event     string `json:"event"`
-		}
+// operationExecutionHistory returns the execution history column value, with
+// times printed by times
+func operationExecutionHistory(times *timeFormatter) func(item proto.Message) string {
+	type printableEvent struct {
+		CreatedAt string `json:"createdAt"`
+		Event     string `json:"event"`
+	}
 
+	return func(item proto.Message) string {
 		history := make([]*printableEvent, 0)
-		execHist := operation.GetExecutionHistory()
-		for _, event := range execHist {
+		for _, event := range item.(*v1.Operation).GetExecutionHistory() {
 			history = append(history, &printableEvent{
-				CreatedAt: event.GetCreatedAt().AsTime().String(),
+				CreatedAt: times.timestamp(event.GetCreatedAt()),
 				Event:     event.GetEvent(),
 			})
 		}
-		values = append(values, fromFieldToJson(history))
-	}
-	return values
-}
-
-func appendInputIfRequested(values []interface{}, operation *v1.Operation, includeInput bool) []interface{} {
-	if includeInput {
-		values = append(values, fromFieldToJson(operation.GetInput()))
+		return fromFieldToJson(history)
 	}
-	return values
 }
 
-func getOperationLeaseInfo(operation *v1.Operation) (string, string) {
+func getOperationLeaseInfo(operation *v1.Operation, times *timeFormatter) (string, string) {
 	leaseTtl := "-"
 	leaseExpired := "-"
 	if operation.Lease != nil {
-		leaseTtl = operation.Lease.GetTtl()
+		leaseTtl = times.leaseTTL(operation.Lease.GetTtl())
 		parsedLeaseTtl, err := time.Parse(time.RFC3339, operation.Lease.GetTtl())
 		if err == nil {
-			expiredSeconds := time.Since(parsedLeaseTtl).Seconds()
+			expiredSeconds := times.now().Sub(parsedLeaseTtl).Seconds()
 			leaseExpired = strings.ToUpper(strconv.FormatBool(expiredSeconds > 0))
 		}
 	}
@@ -8,21 +8,25 @@
 package get
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"github.com/topfreegames/maestro-cli/printer"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestGetOperationsAction(t *testing.T) {
-	config := &extensions.Config{
+	config := &extensions.ContextConfig{
 		ServerURL: "http://localhost:8080",
 	}
 
@@ -56,9 +60,9 @@ func TestGetOperationsAction(t *testing.T) {
 		schedulerName := "test"
 		responseBody, err := protojson.Marshal(operations)
 		require.NoError(t, err)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return(responseBody, 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return(responseBody, 200, nil)
 
-		err = NewGetOperations(client, config).run(nil, []string{schedulerName})
+		err = NewGetOperations(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName})
 		require.NoError(t, err)
 	})
 
@@ -77,9 +81,9 @@ func TestGetOperationsAction(t *testing.T) {
 		schedulerName := "test"
 		responseBody, err := protojson.Marshal(operations)
 		require.NoError(t, err)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return(responseBody, 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return(responseBody, 200, nil)
 
-		err = NewGetOperations(client, config).run(nil, []string{schedulerName})
+		err = NewGetOperations(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName})
 		require.NoError(t, err)
 	})
 
@@ -89,12 +93,12 @@ func TestGetOperationsAction(t *testing.T) {
 
 		schedulerName := "test"
 		client := mocks.NewMockClient(mockCtrl)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return([]byte(""), 404, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return([]byte(""), 404, nil)
 
-		err := NewGetOperations(client, config).run(nil, []string{schedulerName})
+		err := NewGetOperations(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "get operations response not ok, status: Not Found")
+		require.Contains(t, err.Error(), "get operations failed with status Not Found")
 	})
 
 	t.Run("fails when bad format response body", func(t *testing.T) {
@@ -103,9 +107,9 @@ func TestGetOperationsAction(t *testing.T) {
 
 		schedulerName := "test"
 		client := mocks.NewMockClient(mockCtrl)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return([]byte(""), 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return([]byte(""), 200, nil)
 
-		err := NewGetOperations(client, config).run(nil, []string{schedulerName})
+		err := NewGetOperations(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error parsing response body")
@@ -117,9 +121,9 @@ func TestGetOperationsAction(t *testing.T) {
 
 		schedulerName := "test"
 		client := mocks.NewMockClient(mockCtrl)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return([]byte(""), 0, errors.New("request failed"))
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return([]byte(""), 0, errors.New("request failed"))
 
-		err := NewGetOperations(client, config).run(nil, []string{schedulerName})
+		err := NewGetOperations(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error on GET request: request failed")
@@ -162,9 +166,95 @@ func TestGetOperationsAction(t *testing.T) {
 		schedulerName := "test"
 		responseBody, err := protojson.Marshal(operations)
 		require.NoError(t, err)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return(responseBody, 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/"+schedulerName+"/operations", gomock.Any()).Return(responseBody, 200, nil)
 
-		err = NewGetOperations(client, config).run(nil, []string{schedulerName})
+		err = NewGetOperations(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{schedulerName})
 		require.NoError(t, err)
 	})
+
+	t.Run("prints the response as json", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		operations := &v1.ListOperationsResponse{
+			PendingOperations:  []*v1.Operation{{Id: "PENDING_OPERATION", CreatedAt: timestamppb.New(time.Now())}},
+			FinishedOperations: []*v1.Operation{{Id: "FINISHED_OPERATION", CreatedAt: timestamppb.New(time.Now().Add(-time.Hour))}},
+		}
+		responseBody, err := protojson.Marshal(operations)
+		require.NoError(t, err)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/test/operations", gomock.Any()).Return(responseBody, 200, nil)
+
+		output = printer.JSON
+		defer func() { output = printer.Table }()
+		getOperations := NewGetOperations(maestro.NewClient(client, config.ServerURL), config)
+		out := new(bytes.Buffer)
+		getOperations.out = out
+
+		err = getOperations.run(nil, []string{"test"})
+
+		require.NoError(t, err)
+		response := &v1.ListOperationsResponse{}
+		require.NoError(t, protojson.Unmarshal(out.Bytes(), response))
+		// sorting the table rows keeps the response lists
+		require.Equal(t, "PENDING_OPERATION", response.GetPendingOperations()[0].GetId())
+		require.Equal(t, "FINISHED_OPERATION", response.GetFinishedOperations()[0].GetId())
+	})
+
+	t.Run("prints the operations with the table options", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		operations := &v1.ListOperationsResponse{
+			PendingOperations:  []*v1.Operation{{Id: "PENDING_OPERATION", Status: "pending", CreatedAt: timestamppb.New(time.Now())}},
+			ActiveOperations:   []*v1.Operation{{Id: "ACTIVE_OPERATION", Status: "in_progress", CreatedAt: timestamppb.New(time.Now().Add(-2 * time.Hour))}},
+			FinishedOperations: []*v1.Operation{{Id: "FINISHED_OPERATION", Status: "finished", CreatedAt: timestamppb.New(time.Now().Add(-time.Hour))}},
+		}
+		responseBody, err := protojson.Marshal(operations)
+		require.NoError(t, err)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/test/operations", gomock.Any()).Return(responseBody, 200, nil).Times(3)
+
+		getOperations := NewGetOperations(maestro.NewClient(client, config.ServerURL), config)
+		out := new(bytes.Buffer)
+		getOperations.out = out
+		defer func() { tableOptions = printer.TableOptions{} }()
+
+		// newest first
+		tableOptions = printer.TableOptions{Reverse: true, Columns: "ID:.id", NoHeaders: true}
+		err = getOperations.run(nil, []string{"test"})
+		require.NoError(t, err)
+		require.Equal(t, "PENDING_OPERATION\nFINISHED_OPERATION\nACTIVE_OPERATION\n", out.String())
+
+		out.Reset()
+		tableOptions = printer.TableOptions{SortBy: "status", Columns: "ID:.id,STATUS:.status"}
+		err = getOperations.run(nil, []string{"test"})
+		require.NoError(t, err)
+		require.Equal(t, "ID                   STATUS\n"+
+			"FINISHED_OPERATION   finished\n"+
+			"ACTIVE_OPERATION     in_progress\n"+
+			"PENDING_OPERATION    pending\n", out.String())
+
+		out.Reset()
+		output = printer.Name
+		defer func() { output = printer.Table }()
+		tableOptions = printer.TableOptions{SortBy: ".id"}
+		err = getOperations.run(nil, []string{"test"})
+		require.NoError(t, err)
+		require.Equal(t, "ACTIVE_OPERATION\nFINISHED_OPERATION\nPENDING_OPERATION\n", out.String())
+	})
+
+	t.Run("fails on unknown fields before sending requests", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		tableOptions = printer.TableOptions{SortBy: ".lease.expiresAt"}
+		defer func() { tableOptions = printer.TableOptions{} }()
+
+		err := NewGetOperations(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{"test"})
+
+		require.EqualError(t, err, `bad sort field ".lease.expiresAt": unknown field "expiresAt" of Lease, use one of ttl`)
+		require.Equal(t, common.ExitValidation, common.ExitCode(err))
+	})
 }
@@ -0,0 +1,257 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package get
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"github.com/topfreegames/maestro-cli/printer"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var getSchedulerVersion string
+
+// getSchedulerCmd represents the get scheduler command
+var getSchedulerCmd = &cobra.Command{
+	Use:   "scheduler",
+	Short: "Shows a scheduler with its spec",
+	Example: `maestro-cli get scheduler SCHEDULER_NAME
+maestro-cli get scheduler SCHEDULER_NAME --version v1.2.0 -o yaml > scheduler.yaml`,
+	Long: "Shows a scheduler with its containers, port range and forwarders, in its active version unless --version is set. " +
+		"The yaml output prints the scheduler as a create scheduler-version file, without its state and creation time.",
+	Args: validateGetSchedulerArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, config, err := common.GetClientAndConfig()
+		if err != nil {
+			return err
+		}
+
+		return NewGetScheduler(client, config, getSchedulerVersion).run(cmd, args)
+	},
+}
+
+func init() {
+	getSchedulerCmd.Flags().StringVarP(&getSchedulerVersion, "version", "t", "", "Version of the scheduler, defaults to the active one")
+}
+
+func validateGetSchedulerArgs(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("missing arg: scheduler name")
+	}
+	if len(args) > 1 {
+		return errors.New("too many args, get scheduler takes only the scheduler name")
+	}
+
+	return nil
+}
+
+type GetScheduler struct {
+	client  maestro.Client
+	config  *extensions.ContextConfig
+	version string
+	out     io.Writer
+}
+
+func NewGetScheduler(client maestro.Client, config *extensions.ContextConfig, version string) *GetScheduler {
+	return &GetScheduler{
+		client:  client,
+		config:  config,
+		version: version,
+		out:     os.Stdout,
+	}
+}
+
+func (cs *GetScheduler) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
+	times, err := newTimeFormatter(timeFormat, timeZone)
+	if err != nil {
+		return err
+	}
+	resource := schedulerResource(times)
+	p, err := newPrinter(cmd, cs.config, cs.out, resource)
+	if err != nil {
+		return err
+	}
+	logger := common.GetLogger()
+	logger.Debug("getting scheduler")
+
+	schedulerName := args[0]
+	response, err := cs.client.GetScheduler(ctx, &v1.GetSchedulerRequest{SchedulerName: schedulerName, Version: cs.version})
+	if err != nil {
+		return err
+	}
+
+	logger.Sugar().Debugf("success getting scheduler %s", schedulerName)
+
+	scheduler := response.GetScheduler()
+	if (p.Format() == printer.Table || p.Format() == printer.Wide) && tableOptions.Columns == "" {
+		return writeSchedulerView(cs.out, scheduler, times)
+	}
+	var items []proto.Message
+	if scheduler != nil {
+		items = append(items, scheduler)
+	}
+	if p.Format() == printer.YAML {
+		return p.Print(newSchedulerVersionRequest(scheduler), items, resource)
+	}
+	return p.Print(scheduler, items, resource)
+}
+
+// newSchedulerVersionRequest returns the fields of scheduler accepted by
+// create scheduler-version, maestro sets the version of the new one
+func newSchedulerVersionRequest(scheduler *v1.Scheduler) *v1.NewSchedulerVersionRequest {
+	return &v1.NewSchedulerVersionRequest{
+		Name:       scheduler.GetName(),
+		Game:       scheduler.GetGame(),
+		Spec:       scheduler.GetSpec(),
+		PortRange:  scheduler.GetPortRange(),
+		MaxSurge:   scheduler.GetMaxSurge(),
+		Forwarders: scheduler.GetForwarders(),
+	}
+}
+
+// schedulerResource are the columns of get scheduler on the csv, markdown
+// and name outputs or with --columns
+func schedulerResource(times *timeFormatter) *printer.Resource {
+	createdAt := func(item proto.Message) *timestamppb.Timestamp { return item.(*v1.Scheduler).GetCreatedAt() }
+	columns := []printer.Column{
+		{Header: "GAME", Field: ".game", Value: func(item proto.Message) string { return item.(*v1.Scheduler).GetGame() }},
+		{Header: "NAME", Field: ".name", Value: func(item proto.Message) string { return item.(*v1.Scheduler).GetName() }},
+		{Header: "STATE", Field: ".state", Value: func(item proto.Message) string { return item.(*v1.Scheduler).GetState() }},
+		{Header: "VERSION", Field: ".spec.version", Value: func(item proto.Message) string { return item.(*v1.Scheduler).GetSpec().GetVersion() }},
+		times.ageColumn(createdAt),
+		{Header: "PORT_RANGE", Wide: true, Field: ".portRange.start", Value: func(item proto.Message) string {
+			return portRange(item.(*v1.Scheduler).GetPortRange())
+		}},
+		{Header: "MAX_SURGE", Wide: true, Field: ".maxSurge", Value: func(item proto.Message) string { return item.(*v1.Scheduler).GetMaxSurge() }},
+	}
+	return &printer.Resource{
+		Message: &v1.Scheduler{},
+		Columns: append(columns, times.createdAtColumns(createdAt)...),
+		Name:    func(item proto.Message) string { return item.(*v1.Scheduler).GetName() },
+	}
+}
+
+// writeSchedulerView writes the scheduler as indented fields, one per line
+func writeSchedulerView(out io.Writer, scheduler *v1.Scheduler, times *timeFormatter) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	field := func(indent int, name, value string) {
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(w, "%s%s:\t%s\n", strings.Repeat("  ", indent), name, value)
+	}
+	section := func(indent int, name string) {
+		fmt.Fprintf(w, "%s%s:\n", strings.Repeat("  ", indent), name)
+	}
+
+	spec := scheduler.GetSpec()
+	createdAt := times.timestamp(scheduler.GetCreatedAt())
+	if times.format == timeRelative && scheduler.GetCreatedAt() != nil {
+		createdAt += " (" + times.age(scheduler.GetCreatedAt()) + " ago)"
+	}
+	field(0, "Name", scheduler.GetName())
+	field(0, "Game", scheduler.GetGame())
+	field(0, "State", scheduler.GetState())
+	field(0, "Version", spec.GetVersion())
+	field(0, "Created At", createdAt)
+	field(0, "Port Range", portRange(scheduler.GetPortRange()))
+	field(0, "Max Surge", scheduler.GetMaxSurge())
+	field(0, "Termination Grace Period", fmt.Sprint(spec.GetTerminationGracePeriod()))
+	field(0, "Toleration", spec.GetToleration())
+	field(0, "Affinity", spec.GetAffinity())
+
+	section(0, "Containers")
+	for _, container := range spec.GetContainers() {
+		section(1, container.GetName())
+		field(2, "Image", container.GetImage())
+		field(2, "Image Pull Policy", container.GetImagePullPolicy())
+		field(2, "Command", strings.Join(container.GetCommand(), " "))
+		field(2, "Requests", containerResources(container.GetRequests()))
+		field(2, "Limits", containerResources(container.GetLimits()))
+		ports := make([]string, 0, len(container.GetPorts()))
+		for _, port := range container.GetPorts() {
+			ports = append(ports, containerPort(port))
+		}
+		field(2, "Ports", strings.Join(ports, ", "))
+		if len(container.GetEnvironment()) > 0 {
+			section(2, "Environment")
+			for _, env := range container.GetEnvironment() {
+				field(3, env.GetName(), containerEnvironmentValue(env))
+			}
+		}
+	}
+
+	if len(scheduler.GetForwarders()) > 0 {
+		section(0, "Forwarders")
+		for _, forwarder := range scheduler.GetForwarders() {
+			section(1, forwarder.GetName())
+			field(2, "Type", forwarder.GetType())
+			field(2, "Address", forwarder.GetAddress())
+			field(2, "Enabled", fmt.Sprint(forwarder.GetEnable()))
+			if options := forwarder.GetOptions(); options != nil {
+				field(2, "Timeout", fmt.Sprint(options.GetTimeout()))
+				if options.GetMetadata() != nil {
+					metadata, err := protojson.Marshal(options.GetMetadata())
+					if err != nil {
+						return fmt.Errorf("error marshalling forwarder metadata: %w", err)
+					}
+					field(2, "Metadata", string(metadata))
+				}
+			}
+		}
+	}
+	return w.Flush()
+}
+
+func containerResources(resources *v1.ContainerResources) string {
+	var values []string
+	if resources.GetCpu() != "" {
+		values = append(values, "cpu="+resources.GetCpu())
+	}
+	if resources.GetMemory() != "" {
+		values = append(values, "memory="+resources.GetMemory())
+	}
+	return strings.Join(values, ", ")
+}
+
+// containerPort returns the port like default 8080/UDP
+func containerPort(port *v1.ContainerPort) string {
+	text := fmt.Sprintf("%s %d/%s", port.GetName(), port.GetPort(), port.GetProtocol())
+	if port.GetHostPort() != 0 {
+		text += fmt.Sprintf(" (host port %d)", port.GetHostPort())
+	}
+	return strings.TrimSpace(text)
+}
+
+// containerEnvironmentValue returns the value of the variable, or where it
+// comes from
+func containerEnvironmentValue(env *v1.ContainerEnvironment) string {
+	switch {
+	case env.Value != nil:
+		return env.GetValue()
+	case env.GetValueFrom().GetSecretKeyRef() != nil:
+		ref := env.GetValueFrom().GetSecretKeyRef()
+		return fmt.Sprintf("<secret %s, key %s>", ref.GetName(), ref.GetKey())
+	case env.GetValueFrom().GetFieldRef() != nil:
+		return fmt.Sprintf("<field %s>", env.GetValueFrom().GetFieldRef().GetFieldPath())
+	}
+	return ""
+}
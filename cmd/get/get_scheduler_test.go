@@ -0,0 +1,239 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package get
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/cmd/create/scheduler_version"
+	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"github.com/topfreegames/maestro-cli/printer"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	k8s_yaml "sigs.k8s.io/yaml"
+)
+
+func TestGetSchedulerAction(t *testing.T) {
+	config := &extensions.ContextConfig{
+		ServerURL: "http://localhost:8080",
+	}
+	logLevel, metadata := "info", &structpb.Struct{Fields: map[string]*structpb.Value{"roomType": structpb.NewStringValue("red")}}
+	scheduler := &v1.Scheduler{
+		Name:      "scheduler-name",
+		Game:      "game",
+		State:     "in-sync",
+		CreatedAt: timestamppb.New(time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)),
+		MaxSurge:  "10%",
+		PortRange: &v1.PortRange{Start: 10000, End: 20000},
+		Spec: &v1.Spec{
+			Version:                "v1.2.0",
+			TerminationGracePeriod: 100,
+			Toleration:             "game-toleration",
+			Containers: []*v1.Container{
+				{
+					Name:            "game-room",
+					Image:           "game-room:v1",
+					ImagePullPolicy: "Always",
+					Command:         []string{"./run", "--port", "8080"},
+					Environment: []*v1.ContainerEnvironment{
+						{Name: "LOG_LEVEL", Value: &logLevel},
+						{Name: "API_KEY", ValueFrom: &v1.ContainerEnvironmentValueFrom{SecretKeyRef: &v1.ContainerEnvironmentValueFromSecretKeyRef{Name: "game-secrets", Key: "api-key"}}},
+						{Name: "POD_IP", ValueFrom: &v1.ContainerEnvironmentValueFrom{FieldRef: &v1.ContainerEnvironmentValueFromFieldRef{FieldPath: "status.podIP"}}},
+					},
+					Requests: &v1.ContainerResources{Cpu: "100m", Memory: "128Mi"},
+					Limits:   &v1.ContainerResources{Cpu: "200m", Memory: "256Mi"},
+					Ports:    []*v1.ContainerPort{{Name: "default", Protocol: "UDP", Port: 8080}},
+				},
+			},
+		},
+		Forwarders: []*v1.Forwarder{
+			{Name: "matchmaker", Enable: true, Type: "gRPC", Address: "matchmaker:8080", Options: &v1.ForwarderOptions{Timeout: 1000, Metadata: metadata}},
+		},
+	}
+	responseBody, err := protojson.Marshal(&v1.GetSchedulerResponse{Scheduler: scheduler})
+	require.NoError(t, err)
+
+	t.Run("prints the scheduler spec", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return(responseBody, 200, nil)
+
+		timeFormat = timeRFC3339
+		defer func() { timeFormat = timeRelative }()
+		getScheduler := NewGetScheduler(maestro.NewClient(client, config.ServerURL), config, "")
+		out := new(bytes.Buffer)
+		getScheduler.out = out
+
+		err := getScheduler.run(nil, []string{"scheduler-name"})
+
+		require.NoError(t, err)
+		require.Equal(t, `Name:                      scheduler-name
+Game:                      game
+State:                     in-sync
+Version:                   v1.2.0
+Created At:                2022-03-01T12:00:00Z
+Port Range:                10000-20000
+Max Surge:                 10%
+Termination Grace Period:  100
+Toleration:                game-toleration
+Affinity:                  -
+Containers:
+  game-room:
+    Image:              game-room:v1
+    Image Pull Policy:  Always
+    Command:            ./run --port 8080
+    Requests:           cpu=100m, memory=128Mi
+    Limits:             cpu=200m, memory=256Mi
+    Ports:              default 8080/UDP
+    Environment:
+      LOG_LEVEL:  info
+      API_KEY:    <secret game-secrets, key api-key>
+      POD_IP:     <field status.podIP>
+Forwarders:
+  matchmaker:
+    Type:      gRPC
+    Address:   matchmaker:8080
+    Enabled:   true
+    Timeout:   1000
+    Metadata:  {"roomType":"red"}
+`, out.String())
+	})
+
+	t.Run("gets the scheduler version", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/scheduler-name?version=v1.1.0", gomock.Any()).Return(responseBody, 200, nil)
+
+		getScheduler := NewGetScheduler(maestro.NewClient(client, config.ServerURL), config, "v1.1.0")
+		out := new(bytes.Buffer)
+		getScheduler.out = out
+
+		err := getScheduler.run(nil, []string{"scheduler-name"})
+
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Created At:                2022-03-01T12:00:00Z (")
+	})
+
+	t.Run("prints yaml consumed by create scheduler-version", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return(responseBody, 200, nil)
+
+		output = printer.YAML
+		defer func() { output = printer.Table }()
+		getScheduler := NewGetScheduler(maestro.NewClient(client, config.ServerURL), config, "")
+		out := new(bytes.Buffer)
+		getScheduler.out = out
+
+		err := getScheduler.run(nil, []string{"scheduler-name"})
+
+		require.NoError(t, err)
+		require.NotContains(t, out.String(), "state:")
+		require.NotContains(t, out.String(), "createdAt:")
+
+		var sentRequest string
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).DoAndReturn(func(_ context.Context, _, body string) ([]byte, int, error) {
+			sentRequest = body
+			return []byte(`{"operationId": "operation-id"}`), 200, nil
+		})
+		schedulerJSON, err := k8s_yaml.YAMLToJSON(out.Bytes())
+		require.NoError(t, err)
+
+		operationID, err := scheduler_version.NewCreateSchedulerVersion(maestro.NewClient(client, config.ServerURL), config, &common.WaitParameters{}).EnqueueNewSchedulerVersionOperation(context.Background(), schedulerJSON)
+
+		require.NoError(t, err)
+		require.Equal(t, "operation-id", operationID)
+		request := &v1.NewSchedulerVersionRequest{}
+		require.NoError(t, protojson.Unmarshal([]byte(sentRequest), request))
+		require.True(t, proto.Equal(newSchedulerVersionRequest(scheduler), request), "sent %s", sentRequest)
+	})
+
+	t.Run("prints the scheduler with its state on json and jsonpath", func(t *testing.T) {
+		defer func() { output = printer.Table }()
+		for _, testCase := range []struct {
+			Output   string
+			Expected string
+		}{
+			{Output: printer.JSON, Expected: `"state": "in-sync"`},
+			{Output: "jsonpath={.state} {.createdAt}", Expected: "in-sync 2022-03-01T12:00:00Z"},
+			{Output: "go-template={{.state}}", Expected: "in-sync"},
+		} {
+			mockCtrl := gomock.NewController(t)
+			client := mocks.NewMockClient(mockCtrl)
+			client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return(responseBody, 200, nil)
+
+			output = testCase.Output
+			getScheduler := NewGetScheduler(maestro.NewClient(client, config.ServerURL), config, "")
+			out := new(bytes.Buffer)
+			getScheduler.out = out
+
+			err := getScheduler.run(nil, []string{"scheduler-name"})
+
+			require.NoError(t, err, testCase.Output)
+			require.Contains(t, out.String(), testCase.Expected, testCase.Output)
+			mockCtrl.Finish()
+		}
+	})
+
+	t.Run("prints the scheduler columns on csv", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return(responseBody, 200, nil)
+
+		output = printer.CSV
+		defer func() { output = printer.Table }()
+		tableOptions = printer.TableOptions{Columns: "NAME:.name,CONTAINERS:.spec.containers[*].name"}
+		defer func() { tableOptions = printer.TableOptions{} }()
+		getScheduler := NewGetScheduler(maestro.NewClient(client, config.ServerURL), config, "")
+		out := new(bytes.Buffer)
+		getScheduler.out = out
+
+		err := getScheduler.run(nil, []string{"scheduler-name"})
+
+		require.NoError(t, err)
+		require.Equal(t, "NAME,CONTAINERS\nscheduler-name,game-room\n", out.String())
+	})
+
+	t.Run("fails when the scheduler does not exist", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/missing", gomock.Any()).Return([]byte(`{"code": 5, "message": "scheduler missing not found"}`), 404, nil)
+
+		err := NewGetScheduler(maestro.NewClient(client, config.ServerURL), config, "").run(nil, []string{"missing"})
+
+		require.Error(t, err)
+		require.Equal(t, common.ExitNotFound, common.ExitCode(err))
+	})
+
+	t.Run("fails without the scheduler name", func(t *testing.T) {
+		err := validateGetSchedulerArgs(nil, []string{})
+
+		require.EqualError(t, err, "missing arg: scheduler name")
+	})
+}
@@ -9,18 +9,17 @@ package get
 
 import (
 	"fmt"
-	"net/http"
+	"io"
 	"os"
-	"text/tabwriter"
-	"time"
 
-	"github.com/hako/durafmt"
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"github.com/topfreegames/maestro-cli/printer"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
-	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var getSchedulersName, getSchedulersGame, getSchedulersVersion string
@@ -32,15 +31,18 @@ var getSchedulersCmd = &cobra.Command{
 	Example: "maestro-cli get schedulers",
 	Long:    "Lists all schedulers of a given context.",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		client, config, err := common.GetClientAndConfig()
+		if err != nil {
+			return err
+		}
+
 		parameters := &GetSchedulersParameters{
 			Name:    getSchedulersName,
 			Game:    getSchedulersGame,
 			Version: getSchedulersVersion,
 		}
-
-		client, config, err := common.GetClientAndConfig()
-		if err != nil {
-			return err
+		if !cmd.Flags().Changed("game") {
+			parameters.Game = config.GetDefaults().Game
 		}
 
 		return NewGetSchedulers(client, config, parameters).run(cmd, args)
@@ -54,92 +56,108 @@ type GetSchedulersParameters struct {
 }
 
 type GetSchedulers struct {
-	client     interfaces.Client
-	config     *extensions.Config
+	client     maestro.Client
+	config     *extensions.ContextConfig
 	parameters *GetSchedulersParameters
+	out        io.Writer
 }
 
 func init() {
 	getSchedulersCmd.Flags().StringVarP(&getSchedulersName, "name", "n", "", "Add name filter")
-	getSchedulersCmd.Flags().StringVarP(&getSchedulersGame, "game", "g", "", "Add game filter")
+	getSchedulersCmd.Flags().StringVarP(&getSchedulersGame, "game", "g", "", "Add game filter, defaults to the context defaults.game")
 	getSchedulersCmd.Flags().StringVarP(&getSchedulersVersion, "version", "t", "", "Add version filter")
 }
 
-func NewGetSchedulers(client interfaces.Client, config *extensions.Config, parameters *GetSchedulersParameters) *GetSchedulers {
+func NewGetSchedulers(client maestro.Client, config *extensions.ContextConfig, parameters *GetSchedulersParameters) *GetSchedulers {
 	return &GetSchedulers{
 		client:     client,
 		config:     config,
 		parameters: parameters,
+		out:        os.Stdout,
 	}
 }
 
-func (cs *GetSchedulers) run(_ *cobra.Command, args []string) error {
+func (cs *GetSchedulers) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
+	times, err := newTimeFormatter(timeFormat, timeZone)
+	if err != nil {
+		return err
+	}
+	resource := schedulersResource(times)
+	p, err := newPrinter(cmd, cs.config, cs.out, resource)
+	if err != nil {
+		return err
+	}
 
 	logger := common.GetLogger()
 
 	logger.Debug("getting schedulers")
 
-	parameters := buildURLParameters(cs.parameters.Name, cs.parameters.Game, cs.parameters.Version)
-
-	url := fmt.Sprintf("%s/schedulers%s", cs.config.ServerURL, parameters)
-	body, status, err := cs.client.Get(url, "")
-	if err != nil {
-		return fmt.Errorf("error on GET request: %w", err)
+	request := &v1.ListSchedulersRequest{
+		Name:    cs.parameters.Name,
+		Game:    cs.parameters.Game,
+		Version: cs.parameters.Version,
 	}
-	if status != http.StatusOK {
-		return fmt.Errorf("get schedulers response not ok, status: %s, body: %s", http.StatusText(status), string(body))
-	}
-
-	logger.Sugar().Debugf("success getting schedulers: %s", body)
-
-	var schedulers v1.ListSchedulersResponse
-	err = protojson.Unmarshal(body, &schedulers)
+	schedulers, err := cs.client.ListSchedulers(ctx, request)
 	if err != nil {
-		return fmt.Errorf("error parsing response body: %w", err)
+		return err
 	}
 
-	cs.printSchedulersTable(schedulers.Schedulers)
+	logger.Sugar().Debugf("success getting schedulers: %s", schedulers)
 
-	return nil
+	items := make([]proto.Message, 0, len(schedulers.GetSchedulers()))
+	for _, scheduler := range schedulers.GetSchedulers() {
+		items = append(items, scheduler)
+	}
+	return p.Print(schedulers, items, resource)
 }
 
-func (cs *GetSchedulers) printSchedulersTable(schedulers []*v1.SchedulerWithoutSpec) {
-	w := new(tabwriter.Writer)
-
-	// minwidth, tabwidth, padding, padchar, flags
-	w.Init(os.Stdout, 8, 8, 0, '\t', 0)
-
-	defer w.Flush()
-
-	format := "\n %s\t\t%s\t\t%s\t\t%s\t\t%s\t"
-	fmt.Fprintf(w, format, "GAME", "NAME", "STATE", "VERSION", "AGE")
-
-	for _, scheduler := range schedulers {
-		age := time.Since(scheduler.GetCreatedAt().AsTime())
-		prettyAge := durafmt.ParseShort(age).String()
-		fmt.Fprintf(w, format, scheduler.GetGame(), scheduler.GetName(), scheduler.GetState(), scheduler.GetVersion(), prettyAge)
+// schedulersResource are the columns of get schedulers, with times printed
+// by times
+func schedulersResource(times *timeFormatter) *printer.Resource {
+	createdAt := func(item proto.Message) *timestamppb.Timestamp { return item.(*v1.SchedulerWithoutSpec).GetCreatedAt() }
+	columns := []printer.Column{
+		{Header: "GAME", Field: ".game", Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetGame() }},
+		{Header: "NAME", Field: ".name", Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetName() }},
+		{
+			Header: "STATE",
+			Field:  ".state",
+			Value:  func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetState() },
+			Color: func(item proto.Message) printer.Color {
+				return schedulerStateColor(item.(*v1.SchedulerWithoutSpec).GetState())
+			},
+		},
+		{Header: "VERSION", Field: ".version", Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetVersion() }},
+		times.ageColumn(createdAt),
+		{Header: "PORT_RANGE", Wide: true, Field: ".portRange.start", Value: func(item proto.Message) string {
+			return portRange(item.(*v1.SchedulerWithoutSpec).GetPortRange())
+		}},
+		{Header: "MAX_SURGE", Wide: true, Field: ".maxSurge", Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetMaxSurge() }},
+	}
+	return &printer.Resource{
+		Message: &v1.SchedulerWithoutSpec{},
+		Columns: append(columns, times.createdAtColumns(createdAt)...),
+		Name:    func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetName() },
 	}
 }
 
-func buildURLParameters(name, game, version string) string {
-	parameters := ""
-
-	parameters = appendParameter(parameters, "name", name)
-	parameters = appendParameter(parameters, "game", game)
-	parameters = appendParameter(parameters, "version", version)
-
-	return parameters
+// schedulerStateColor colors the scheduler states: in sync, in progress and
+// on error
+func schedulerStateColor(state string) printer.Color {
+	switch state {
+	case "in-sync":
+		return printer.Green
+	case "creating", "terminating":
+		return printer.Yellow
+	case "on-error":
+		return printer.Red
+	}
+	return printer.NoColor
 }
 
-func appendParameter(parameters, parameterName, parameter string) string {
-	if parameter != "" {
-		if len(parameters) == 0 {
-			parameters = "?"
-		} else {
-			parameters = parameters + "&"
-		}
-		parameters = parameters + fmt.Sprintf("%s=%s", parameterName, parameter)
+func portRange(portRange *v1.PortRange) string {
+	if portRange == nil {
+		return "-"
 	}
-
-	return parameters
+	return fmt.Sprintf("%d-%d", portRange.GetStart(), portRange.GetEnd())
 }
@@ -1,25 +1,24 @@
 package get
 
 import (
-	"fmt"
-	"net/http"
+	"io"
 	"os"
 	"strconv"
-	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"github.com/topfreegames/maestro-cli/printer"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
-	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 var getSchedulersInfoCmd = &cobra.Command{
 	Use:     "schedulers-info",
 	Short:   "List information from schedulers and game rooms",
 	Example: "maestro-cli get schedulers-info <game>",
-	Long:    "Lists schedulers and game rooms information for all schedulers or from specific game.",
+	Long:    "Lists schedulers and game rooms information for all schedulers or from specific game, defaults to the context defaults.game.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, config, err := common.GetClientAndConfig()
 		if err != nil {
@@ -31,63 +30,67 @@ var getSchedulersInfoCmd = &cobra.Command{
 }
 
 type GetSchedulersInfo struct {
-	client interfaces.Client
-	config *extensions.Config
+	client maestro.Client
+	config *extensions.ContextConfig
+	out    io.Writer
 }
 
-func NewGetSchedulersInfo(client interfaces.Client, config *extensions.Config) *GetSchedulersInfo {
+func NewGetSchedulersInfo(client maestro.Client, config *extensions.ContextConfig) *GetSchedulersInfo {
 	return &GetSchedulersInfo{
 		client: client,
 		config: config,
+		out:    os.Stdout,
 	}
 }
 
-func (s *GetSchedulersInfo) run(_ *cobra.Command, args []string) error {
+func (s *GetSchedulersInfo) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
+	p, err := newPrinter(cmd, s.config, s.out, schedulersInfoResource)
+	if err != nil {
+		return err
+	}
 	logger := common.GetLogger()
-	var game string
+	game := s.config.GetDefaults().Game
 	if len(args) > 0 {
 		game = args[0]
 	}
 
-	var url string
 	if game != "" {
 		logger.Debug("get schedulers information to game: " + game)
-		url = fmt.Sprintf("%s/schedulers/info?game=/%s", s.config.ServerURL, game)
 	} else {
 		logger.Debug("get schedulers information to all schedulers")
-		url = fmt.Sprintf("%s/schedulers/info", s.config.ServerURL)
 	}
-	body, status, err := s.client.Get(url, "")
+	schedulers, err := s.client.GetSchedulersInfo(ctx, &v1.GetSchedulersInfoRequest{Game: game})
 	if err != nil {
-		return fmt.Errorf("error on GET request: %w", err)
-	}
-	if status != http.StatusOK {
-		return fmt.Errorf("get schedulers info by game response not ok, status: %s, body: %s", http.StatusText(status), string(body))
+		return err
 	}
 
-	var schedulers v1.GetSchedulersInfoResponse
-	err = protojson.Unmarshal(body, &schedulers)
-	if err != nil {
-		return fmt.Errorf("error parsing response body: %w", err)
+	items := make([]proto.Message, 0, len(schedulers.GetSchedulers()))
+	for _, scheduler := range schedulers.GetSchedulers() {
+		items = append(items, scheduler)
 	}
-
-	s.printSchedulersTable(schedulers.Schedulers)
-
-	return nil
+	return p.Print(schedulers, items, schedulersInfoResource)
 }
 
-func (s *GetSchedulersInfo) printSchedulersTable(schedulers []*v1.SchedulerInfo) {
-	w := new(tabwriter.Writer)
-
-	// minwidth, tabwidth, padding, padchar, flags
-	w.Init(os.Stdout, 8, 8, 0, '\t', 0)
-
-	defer w.Flush()
-
-	format := "\n %s\t\t%s\t\t%s\t\t%s\t\t%s\t\t%s\t\t%s\t"
-	fmt.Fprintf(w, format, "SCHEDULER", "GAME", "STATE", "ROOM_SREADY", "ROOMS_OCCUPIED", "ROOMS_CREATING", "ROOMS_TERMINATING")
-
-	for _, scheduler := range schedulers {
-		fmt.Fprintf(w, format, scheduler.GetName(), scheduler.GetName(), scheduler.GetState(), strconv.Itoa(int(scheduler.GetRoomsReady())), strconv.Itoa(int(scheduler.GetRoomsOccupied())), strconv.Itoa(int(scheduler.GetRoomsPending())), strconv.Itoa(int(scheduler.GetRoomsTerminating())))
-	}
+var schedulersInfoResource = &printer.Resource{
+	Message: &v1.SchedulerInfo{},
+	Columns: []printer.Column{
+		{Header: "SCHEDULER", Field: ".name", Value: func(item proto.Message) string { return item.(*v1.SchedulerInfo).GetName() }},
+		{Header: "GAME", Field: ".game", Value: func(item proto.Message) string { return item.(*v1.SchedulerInfo).GetGame() }},
+		{
+			Header: "STATE",
+			Field:  ".state",
+			Value:  func(item proto.Message) string { return item.(*v1.SchedulerInfo).GetState() },
+			Color: func(item proto.Message) printer.Color {
+				return schedulerStateColor(item.(*v1.SchedulerInfo).GetState())
+			},
+		},
+		{Header: "ROOMS_READY", Field: ".roomsReady", Value: func(item proto.Message) string { return strconv.Itoa(int(item.(*v1.SchedulerInfo).GetRoomsReady())) }},
+		{Header: "ROOMS_OCCUPIED", Field: ".roomsOccupied", Value: func(item proto.Message) string { return strconv.Itoa(int(item.(*v1.SchedulerInfo).GetRoomsOccupied())) }},
+		{Header: "ROOMS_CREATING", Field: ".roomsPending", Value: func(item proto.Message) string { return strconv.Itoa(int(item.(*v1.SchedulerInfo).GetRoomsPending())) }},
+		{Header: "ROOMS_TERMINATING", Field: ".roomsTerminating", Value: func(item proto.Message) string {
+			return strconv.Itoa(int(item.(*v1.SchedulerInfo).GetRoomsTerminating()))
+		}},
+	},
+	Name: func(item proto.Message) string { return item.(*v1.SchedulerInfo).GetName() },
 }
@@ -1,6 +1,7 @@
 package get
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 
@@ -8,12 +9,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"github.com/topfreegames/maestro-cli/printer"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func TestGetSchedulersInfoAction(t *testing.T) {
-	config := &extensions.Config{
+	config := &extensions.ContextConfig{
 		ServerURL: "http://localhost:8080",
 	}
 
@@ -35,9 +38,75 @@ func TestGetSchedulersInfoAction(t *testing.T) {
 			},
 		}
 		responseBody, _ := protojson.Marshal(schedulers)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/info", gomock.Any()).Return(responseBody, 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/info", gomock.Any()).Return(responseBody, 200, nil)
 
-		err := NewGetSchedulersInfo(client, config).run(nil, []string{})
+		err := NewGetSchedulersInfo(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("exports the table as csv and markdown", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		client := mocks.NewMockClient(mockCtrl)
+		schedulers := &v1.GetSchedulersInfoResponse{
+			Schedulers: []*v1.SchedulerInfo{
+				{Name: "scheduler-test-1", Game: "the-game", State: "in-sync", RoomsReady: 10, RoomsOccupied: 20},
+				{Name: "scheduler-test-2", Game: "the,game", State: "on-error", RoomsReady: 2},
+			},
+		}
+		responseBody, _ := protojson.Marshal(schedulers)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/info", gomock.Any()).Return(responseBody, 200, nil).Times(2)
+		getSchedulersInfo := NewGetSchedulersInfo(maestro.NewClient(client, config.ServerURL), config)
+		out := new(bytes.Buffer)
+		getSchedulersInfo.out = out
+		defer func() { output = printer.Table }()
+
+		output = printer.CSV
+		err := getSchedulersInfo.run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Equal(t, "SCHEDULER,GAME,STATE,ROOMS_READY,ROOMS_OCCUPIED,ROOMS_CREATING,ROOMS_TERMINATING\n"+
+			"scheduler-test-1,the-game,in-sync,10,20,0,0\n"+
+			"scheduler-test-2,\"the,game\",on-error,2,0,0,0\n", out.String())
+
+		out.Reset()
+		output = printer.Markdown
+		err = getSchedulersInfo.run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Equal(t, "| SCHEDULER | GAME | STATE | ROOMS_READY | ROOMS_OCCUPIED | ROOMS_CREATING | ROOMS_TERMINATING |\n"+
+			"| --- | --- | --- | --- | --- | --- | --- |\n"+
+			"| scheduler-test-1 | the-game | in-sync | 10 | 20 | 0 | 0 |\n"+
+			"| scheduler-test-2 | the,game | on-error | 2 | 0 | 0 | 0 |\n", out.String())
+	})
+
+	t.Run("filters by the context default game", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		client := mocks.NewMockClient(mockCtrl)
+		config := &extensions.ContextConfig{
+			ServerURL: "http://localhost:8080",
+			Defaults:  &extensions.Defaults{Game: "the-game"},
+		}
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/info?game=the-game", gomock.Any()).Return([]byte("{}"), 200, nil)
+
+		err := NewGetSchedulersInfo(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("game arg overrides the context default game", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		client := mocks.NewMockClient(mockCtrl)
+		config := &extensions.ContextConfig{
+			ServerURL: "http://localhost:8080",
+			Defaults:  &extensions.Defaults{Game: "the-game"},
+		}
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/info?game=other-game", gomock.Any()).Return([]byte("{}"), 200, nil)
+
+		err := NewGetSchedulersInfo(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{"other-game"})
 
 		require.NoError(t, err)
 	})
@@ -46,21 +115,21 @@ func TestGetSchedulersInfoAction(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		client := mocks.NewMockClient(mockCtrl)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/info", gomock.Any()).Return([]byte(""), 404, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/info", gomock.Any()).Return([]byte(""), 404, nil)
 
-		err := NewGetSchedulersInfo(client, config).run(nil, []string{})
+		err := NewGetSchedulersInfo(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "get schedulers info by game response not ok, status: Not Found")
+		require.Contains(t, err.Error(), "get schedulers info failed with status Not Found")
 	})
 
 	t.Run("fails when bad format response body", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		client := mocks.NewMockClient(mockCtrl)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/info", gomock.Any()).Return([]byte(""), 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/info", gomock.Any()).Return([]byte(""), 200, nil)
 
-		err := NewGetSchedulersInfo(client, config).run(nil, []string{})
+		err := NewGetSchedulersInfo(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error parsing response body")
@@ -70,9 +139,9 @@ func TestGetSchedulersInfoAction(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		client := mocks.NewMockClient(mockCtrl)
-		client.EXPECT().Get(config.ServerURL+"/schedulers/info", gomock.Any()).Return([]byte(""), 0, errors.New("request failed"))
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/info", gomock.Any()).Return([]byte(""), 0, errors.New("request failed"))
 
-		err := NewGetSchedulersInfo(client, config).run(nil, []string{})
+		err := NewGetSchedulersInfo(maestro.NewClient(client, config.ServerURL), config).run(nil, []string{})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error on GET request: request failed")
@@ -8,20 +8,25 @@
 package get
 
 import (
+	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"github.com/topfreegames/maestro-cli/printer"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestGetSchedulersAction(t *testing.T) {
-	config := &extensions.Config{
+	config := &extensions.ContextConfig{
 		ServerURL: "http://localhost:8080",
 	}
 
@@ -45,11 +50,11 @@ func TestGetSchedulersAction(t *testing.T) {
 
 		responseBody, _ := protojson.Marshal(schedulers)
 
-		client.EXPECT().Get(config.ServerURL+"/schedulers", gomock.Any()).Return(responseBody, 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers", gomock.Any()).Return(responseBody, 200, nil)
 
 		parameters := &GetSchedulersParameters{}
 
-		err := NewGetSchedulers(client, config, parameters).run(nil, []string{})
+		err := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, parameters).run(nil, []string{})
 
 		require.NoError(t, err)
 	})
@@ -74,11 +79,11 @@ func TestGetSchedulersAction(t *testing.T) {
 
 		responseBody, _ := protojson.Marshal(schedulers)
 
-		client.EXPECT().Get(config.ServerURL+"/schedulers?name=some name", gomock.Any()).Return(responseBody, 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers?name=some+name", gomock.Any()).Return(responseBody, 200, nil)
 
 		parameters := &GetSchedulersParameters{Name: "some name"}
 
-		err := NewGetSchedulers(client, config, parameters).run(nil, []string{})
+		err := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, parameters).run(nil, []string{})
 
 		require.NoError(t, err)
 	})
@@ -88,14 +93,14 @@ func TestGetSchedulersAction(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		client := mocks.NewMockClient(mockCtrl)
-		client.EXPECT().Get(config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(""), 404, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(""), 404, nil)
 
 		parameters := &GetSchedulersParameters{}
 
-		err := NewGetSchedulers(client, config, parameters).run(nil, []string{})
+		err := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, parameters).run(nil, []string{})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "get schedulers response not ok, status: Not Found")
+		require.Contains(t, err.Error(), "get schedulers failed with status Not Found")
 	})
 
 	t.Run("fails when bad format response body", func(t *testing.T) {
@@ -103,11 +108,11 @@ func TestGetSchedulersAction(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		client := mocks.NewMockClient(mockCtrl)
-		client.EXPECT().Get(config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(""), 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(""), 200, nil)
 
 		parameters := &GetSchedulersParameters{}
 
-		err := NewGetSchedulers(client, config, parameters).run(nil, []string{})
+		err := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, parameters).run(nil, []string{})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error parsing response body")
@@ -118,64 +123,119 @@ func TestGetSchedulersAction(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		client := mocks.NewMockClient(mockCtrl)
-		client.EXPECT().Get(config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(""), 0, errors.New("request failed"))
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers", gomock.Any()).Return([]byte(""), 0, errors.New("request failed"))
 
 		parameters := &GetSchedulersParameters{}
 
-		err := NewGetSchedulers(client, config, parameters).run(nil, []string{})
+		err := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, parameters).run(nil, []string{})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error on GET request: request failed")
 	})
-}
 
-func TestBuildURLParameters(t *testing.T) {
-	type input struct {
-		Name    string
-		Game    string
-		Version string
-	}
+	t.Run("prints the schedulers in the output format", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
 
-	testCases := []struct {
-		Title          string
-		Input          input
-		ExpectedReturn string
-	}{
-		{
-			Title:          "when there is only name",
-			Input:          input{Name: "some-name"},
-			ExpectedReturn: "?name=some-name",
-		}, {
-			Title:          "when there is only game",
-			Input:          input{Game: "some-game"},
-			ExpectedReturn: "?game=some-game",
-		}, {
-			Title:          "when there is only version",
-			Input:          input{Version: "some-version"},
-			ExpectedReturn: "?version=some-version",
-		}, {
-			Title:          "when there are name and game",
-			Input:          input{Name: "some-name", Game: "some-game"},
-			ExpectedReturn: "?name=some-name&game=some-game",
-		}, {
-			Title:          "when there are name and version",
-			Input:          input{Name: "some-name", Version: "some-version"},
-			ExpectedReturn: "?name=some-name&version=some-version",
-		}, {
-			Title:          "when there are game and version",
-			Input:          input{Game: "some-game", Version: "some-version"},
-			ExpectedReturn: "?game=some-game&version=some-version",
-		}, {
-			Title:          "when there are all parameters",
-			Input:          input{Game: "some-game", Version: "some-version"},
-			ExpectedReturn: "?game=some-game&version=some-version",
-		},
-	}
+		client := mocks.NewMockClient(mockCtrl)
+		responseBody, _ := protojson.Marshal(&v1.ListSchedulersResponse{
+			Schedulers: []*v1.SchedulerWithoutSpec{{Name: "scheduler-test-1"}, {Name: "scheduler-test-2"}},
+		})
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers", gomock.Any()).Return(responseBody, 200, nil).Times(2)
+
+		output = printer.Name
+		defer func() { output = printer.Table }()
+		getSchedulers := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, &GetSchedulersParameters{})
+		out := new(bytes.Buffer)
+		getSchedulers.out = out
+
+		err := getSchedulers.run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Equal(t, "scheduler-test-1\nscheduler-test-2\n", out.String())
+
+		// the context defaults.output is used unless --output is set
+		getSchedulers.config = &extensions.ContextConfig{ServerURL: config.ServerURL, Defaults: &extensions.Defaults{Output: printer.JSON}}
+		out.Reset()
+
+		err = getSchedulers.run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `"name": "scheduler-test-2"`)
+	})
 
-	for _, testCase := range testCases {
-		t.Run(testCase.Title, func(t *testing.T) {
-			returnedParameters := buildURLParameters(testCase.Input.Name, testCase.Input.Game, testCase.Input.Version)
-			require.Equal(t, testCase.ExpectedReturn, returnedParameters)
+	t.Run("prints the schedulers with a jsonpath template", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		responseBody, _ := protojson.Marshal(&v1.ListSchedulersResponse{
+			Schedulers: []*v1.SchedulerWithoutSpec{{Name: "scheduler-test-1"}, {Name: "scheduler-test-2"}},
 		})
-	}
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers", gomock.Any()).Return(responseBody, 200, nil)
+
+		output = "jsonpath={.schedulers[*].name}"
+		defer func() { output = printer.Table }()
+		getSchedulers := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, &GetSchedulersParameters{})
+		out := new(bytes.Buffer)
+		getSchedulers.out = out
+
+		err := getSchedulers.run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Equal(t, "scheduler-test-1 scheduler-test-2", out.String())
+	})
+
+	t.Run("prints the creation time in the time format", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		responseBody, _ := protojson.Marshal(&v1.ListSchedulersResponse{
+			Schedulers: []*v1.SchedulerWithoutSpec{
+				{Name: "scheduler-test-1", Game: "game", State: "in-sync", Version: "v1", CreatedAt: timestamppb.New(time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC))},
+			},
+		})
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers", gomock.Any()).Return(responseBody, 200, nil)
+
+		timeFormat, timeZone = timeRFC3339, "America/Sao_Paulo"
+		defer func() { timeFormat, timeZone = timeRelative, "" }()
+		getSchedulers := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, &GetSchedulersParameters{})
+		out := new(bytes.Buffer)
+		getSchedulers.out = out
+
+		err := getSchedulers.run(nil, []string{})
+
+		require.NoError(t, err)
+		require.Equal(t, "GAME   NAME               STATE     VERSION   CREATED_AT\n"+
+			"game   scheduler-test-1   in-sync   v1        2022-03-01T09:00:00-03:00\n", out.String())
+	})
+
+	t.Run("fails on unknown time formats before sending requests", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		timeFormat = "unix"
+		defer func() { timeFormat = timeRelative }()
+
+		err := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, &GetSchedulersParameters{}).run(nil, []string{})
+
+		require.EqualError(t, err, `bad time format "unix", use one of relative, rfc3339, local`)
+		require.Equal(t, common.ExitValidation, common.ExitCode(err))
+	})
+
+	t.Run("fails on unknown output formats before sending requests", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+		output = "xml"
+		defer func() { output = printer.Table }()
+
+		err := NewGetSchedulers(maestro.NewClient(client, config.ServerURL), config, &GetSchedulersParameters{}).run(nil, []string{})
+
+		require.EqualError(t, err, "bad output format \"xml\", use one of table, wide, json, yaml, name, csv, markdown, or go-template|go-template-file|jsonpath|jsonpath-file=TEMPLATE")
+		require.Equal(t, common.ExitValidation, common.ExitCode(err))
+	})
 }
@@ -0,0 +1,128 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package get
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hako/durafmt"
+	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/printer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// timeRelative prints ages and lease TTLs relative to now, e.g. 2 hours
+	// or in 30 seconds, the default
+	timeRelative = "relative"
+	// timeRFC3339 prints times as RFC3339 timestamps, in UTC unless --tz
+	// is set
+	timeRFC3339 = "rfc3339"
+	// timeLocal prints times like 2022-03-01 09:00:00 -03, in the local
+	// time zone unless --tz is set
+	timeLocal = "local"
+)
+
+var timeFormats = []string{timeRelative, timeRFC3339, timeLocal}
+
+// timeFormat and timeZone are set by --time-format and --tz
+var timeFormat, timeZone string
+
+// timeFormatter prints the times of the tables
+type timeFormatter struct {
+	format   string
+	location *time.Location
+	now      func() time.Time
+}
+
+func newTimeFormatter(format, zone string) (*timeFormatter, error) {
+	valid := false
+	for _, f := range timeFormats {
+		valid = valid || format == f
+	}
+	if !valid {
+		return nil, common.NewValidationError(fmt.Errorf("bad time format %q, use one of %s", format, strings.Join(timeFormats, ", ")))
+	}
+
+	location := time.UTC
+	if format == timeLocal {
+		location = time.Local
+	}
+	if zone != "" {
+		var err error
+		location, err = time.LoadLocation(zone)
+		if err != nil {
+			return nil, common.NewValidationError(fmt.Errorf("bad time zone %q: %w", zone, err))
+		}
+	}
+	return &timeFormatter{format: format, location: location, now: time.Now}, nil
+}
+
+// ageColumn is the AGE column on the relative format, and a CREATED_AT one
+// with the timestamp on the others
+func (f *timeFormatter) ageColumn(createdAt func(item proto.Message) *timestamppb.Timestamp) printer.Column {
+	if f.format != timeRelative {
+		return printer.Column{Header: "CREATED_AT", Field: ".createdAt", Value: func(item proto.Message) string { return f.timestamp(createdAt(item)) }}
+	}
+	return printer.Column{Header: "AGE", Field: ".createdAt", Value: func(item proto.Message) string { return f.age(createdAt(item)) }}
+}
+
+// createdAtColumns are the wide CREATED_AT column on the relative format,
+// the others have it in place of AGE
+func (f *timeFormatter) createdAtColumns(createdAt func(item proto.Message) *timestamppb.Timestamp) []printer.Column {
+	if f.format != timeRelative {
+		return nil
+	}
+	return []printer.Column{
+		{Header: "CREATED_AT", Wide: true, Field: ".createdAt", Value: func(item proto.Message) string { return f.timestamp(createdAt(item)) }},
+	}
+}
+
+// age returns how long ago t was, e.g. 2 hours
+func (f *timeFormatter) age(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return "-"
+	}
+	return durafmt.ParseShort(f.now().Sub(t.AsTime())).String()
+}
+
+// timestamp returns t in the time format, as RFC3339 on the relative one
+func (f *timeFormatter) timestamp(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return "-"
+	}
+	return f.formatTime(t.AsTime())
+}
+
+func (f *timeFormatter) formatTime(t time.Time) string {
+	t = t.In(f.location)
+	if f.format == timeLocal {
+		return t.Format("2006-01-02 15:04:05 MST")
+	}
+	return t.Format(time.RFC3339)
+}
+
+// leaseTTL returns when the RFC3339 ttl of a lease expires, e.g. in 30 seconds
+// or 2 minutes ago on the relative format. Bad TTLs are returned as they are.
+func (f *timeFormatter) leaseTTL(ttl string) string {
+	parsed, err := time.Parse(time.RFC3339, ttl)
+	if err != nil {
+		return ttl
+	}
+	if f.format != timeRelative {
+		return f.formatTime(parsed)
+	}
+	remaining := parsed.Sub(f.now())
+	if remaining < 0 {
+		return durafmt.ParseShort(-remaining).String() + " ago"
+	}
+	return "in " + durafmt.ParseShort(remaining).String()
+}
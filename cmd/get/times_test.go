@@ -0,0 +1,86 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package get
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/common"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTimeFormatter(t *testing.T) {
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	createdAt := timestamppb.New(now.Add(-2 * time.Hour))
+
+	testCases := []struct {
+		Format            string
+		Zone              string
+		ExpectedAge       string
+		ExpectedTimestamp string
+		ExpectedTTL       string
+		ExpectedExpired   string
+	}{
+		{
+			Format:            timeRelative,
+			ExpectedAge:       "2 hours",
+			ExpectedTimestamp: "2022-03-01T10:00:00Z",
+			ExpectedTTL:       "in 30 seconds",
+			ExpectedExpired:   "1 minute ago",
+		}, {
+			Format:            timeRFC3339,
+			ExpectedAge:       "2022-03-01T10:00:00Z",
+			ExpectedTimestamp: "2022-03-01T10:00:00Z",
+			ExpectedTTL:       "2022-03-01T12:00:30Z",
+			ExpectedExpired:   "2022-03-01T11:59:00Z",
+		}, {
+			Format:            timeRFC3339,
+			Zone:              "America/Sao_Paulo",
+			ExpectedAge:       "2022-03-01T07:00:00-03:00",
+			ExpectedTimestamp: "2022-03-01T07:00:00-03:00",
+			ExpectedTTL:       "2022-03-01T09:00:30-03:00",
+			ExpectedExpired:   "2022-03-01T08:59:00-03:00",
+		}, {
+			Format:            timeLocal,
+			Zone:              "Asia/Tokyo",
+			ExpectedAge:       "2022-03-01 19:00:00 JST",
+			ExpectedTimestamp: "2022-03-01 19:00:00 JST",
+			ExpectedTTL:       "2022-03-01 21:00:30 JST",
+			ExpectedExpired:   "2022-03-01 20:59:00 JST",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Format+" "+testCase.Zone, func(t *testing.T) {
+			times, err := newTimeFormatter(testCase.Format, testCase.Zone)
+			require.NoError(t, err)
+			times.now = func() time.Time { return now }
+
+			require.Equal(t, testCase.ExpectedAge, times.ageColumn(func(_ proto.Message) *timestamppb.Timestamp { return createdAt }).Value(nil))
+			require.Equal(t, testCase.ExpectedTimestamp, times.timestamp(createdAt))
+			require.Equal(t, testCase.ExpectedTTL, times.leaseTTL(now.Add(30*time.Second).Format(time.RFC3339)))
+			require.Equal(t, testCase.ExpectedExpired, times.leaseTTL(now.Add(-time.Minute).Format(time.RFC3339)))
+			require.Equal(t, "not-a-time", times.leaseTTL("not-a-time"))
+			require.Equal(t, "-", times.timestamp(nil))
+		})
+	}
+
+	t.Run("fails on bad formats and zones", func(t *testing.T) {
+		_, err := newTimeFormatter("iso", "")
+		require.EqualError(t, err, `bad time format "iso", use one of relative, rfc3339, local`)
+		require.Equal(t, common.ExitValidation, common.ExitCode(err))
+
+		_, err = newTimeFormatter(timeRFC3339, "Mars/Olympus_Mons")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `bad time zone "Mars/Olympus_Mons"`)
+		require.Equal(t, common.ExitValidation, common.ExitCode(err))
+	})
+}
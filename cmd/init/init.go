@@ -10,20 +10,49 @@ package init
 import (
 	"errors"
 	"fmt"
-	"net/url"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/interfaces"
 )
 
+var authToken, authTokenFile, credentialHelper, oauth2Flow, oauth2TokenURL, oauth2DeviceAuthURL, oauth2ClientID, oauth2ClientSecret string
+var oauth2Scopes []string
+var caFile, certFile, keyFile, serverName, proxy string
+var insecureSkipVerify bool
+
 // initCmd represents the init maestro-cli command
 var Cmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize maestro-cli",
-	Long:  `Creates the directory ~/.maestro and appends a default config file`,
-	Args:  validateArgs,
-	Run:   run,
+	Long: "Creates the directory ~/.maestro and adds the context to its config file. The first context added becomes the current one. " +
+		"Running it on an existing context only changes the server URL and the settings of the flags passed, the auth settings are replaced when a token, token file, credential helper or OAuth2 flow is passed.",
+	Args: validateArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewInit(extensions.NewFileSystem(), os.Stdout).run(cmd, args)
+	},
+}
+
+func init() {
+	Cmd.Flags().StringVar(&authToken, "auth-token", "", "Static token sent on the Authorization header")
+	Cmd.Flags().StringVar(&authTokenFile, "auth-token-file", "", "File with the token sent on the Authorization header, read on every request")
+	Cmd.Flags().StringVar(&credentialHelper, "credential-helper", "", "Executable that returns the token, run with the context and server URL as JSON on stdin")
+	Cmd.Flags().StringVar(&oauth2Flow, "oauth2-flow", "", "OAuth2 flow used by maestro-cli login, client-credentials or device-code")
+	Cmd.Flags().StringVar(&oauth2TokenURL, "oauth2-token-url", "", "OAuth2 token endpoint")
+	Cmd.Flags().StringVar(&oauth2DeviceAuthURL, "oauth2-device-auth-url", "", "OAuth2 device authorization endpoint, required by the device-code flow")
+	Cmd.Flags().StringVar(&oauth2ClientID, "oauth2-client-id", "", "OAuth2 client id")
+	Cmd.Flags().StringVar(&oauth2ClientSecret, "oauth2-client-secret", "", "OAuth2 client secret, required by the client-credentials flow")
+	Cmd.Flags().StringSliceVar(&oauth2Scopes, "oauth2-scopes", nil, "OAuth2 scopes requested")
+	Cmd.Flags().StringVar(&caFile, "ca-file", "", "PEM encoded CA bundle used to verify the server certificate")
+	Cmd.Flags().StringVar(&certFile, "cert-file", "", "PEM encoded client certificate, for servers requiring mTLS")
+	Cmd.Flags().StringVar(&keyFile, "key-file", "", "PEM encoded client certificate key, for servers requiring mTLS")
+	Cmd.Flags().StringVar(&serverName, "server-name", "", "Server name used to verify the server certificate, defaults to the server URL host")
+	Cmd.Flags().BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "Do not verify the server certificate, use only for testing")
+	Cmd.Flags().StringVar(&proxy, "proxy", "", "http, https or socks5 proxy URL of the requests, hosts listed by NO_PROXY are reached directly")
 }
 
 func validateArgs(_ *cobra.Command, args []string) error {
@@ -34,23 +63,141 @@ func validateArgs(_ *cobra.Command, args []string) error {
 		return errors.New("missing arg with maestro server URL")
 	}
 
-	_, err := url.ParseRequestURI(args[1])
+	_, err := extensions.ParseServerURL(args[1])
 	if err != nil {
 		return errors.New("bad maestro server URl")
 	}
-
 	return nil
 }
 
-func run(_ *cobra.Command, args []string) {
+type Init struct {
+	fs  interfaces.FileSystem
+	out io.Writer
+}
+
+func NewInit(fs interfaces.FileSystem, out io.Writer) *Init {
+	return &Init{
+		fs:  fs,
+		out: out,
+	}
+}
+
+func (i *Init) run(cmd *cobra.Command, args []string) error {
 	context := args[0]
 	serverURL := args[1]
-	config := extensions.NewConfig(serverURL)
-	err := config.Write(extensions.NewFileSystem(), context)
+	config, err := extensions.ReadConfig(i.fs)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	existing := config.Contexts[context]
+	contextConfig := buildContextConfig(serverURL)
+	if existing != nil {
+		contextConfig = mergeContextConfig(existing, serverURL, cmd.Flags())
+	}
+	err = contextConfig.Validate()
+	if err != nil {
+		return common.NewValidationError(err)
+	}
+	_, err = extensions.NewTLSConfig(contextConfig)
+	if err != nil {
+		return common.NewValidationError(err)
+	}
+
+	config.SetContext(context, contextConfig)
+	err = config.Write(i.fs)
 	if err != nil {
-		fmt.Println("Error writing config file: ", err)
-		os.Exit(1)
+		return fmt.Errorf("error writing config file: %w", err)
 	}
 
-	fmt.Println("Configuration created")
+	if existing != nil {
+		fmt.Fprintln(i.out, "Configuration updated")
+	} else {
+		fmt.Fprintln(i.out, "Configuration created")
+	}
+	if contextConfig.Auth != nil && contextConfig.Auth.OAuth2 != nil && contextConfig.Auth.OAuth2.CachedToken == nil {
+		fmt.Fprintf(i.out, "Run maestro-cli login %s to obtain an auth token\n", context)
+	}
+	return nil
+}
+
+func buildContextConfig(serverURL string) *extensions.ContextConfig {
+	contextConfig := extensions.NewContextConfig(serverURL)
+	contextConfig.Auth = buildAuthConfig()
+	contextConfig.CAFile = caFile
+	contextConfig.CertFile = certFile
+	contextConfig.KeyFile = keyFile
+	contextConfig.ServerName = serverName
+	contextConfig.InsecureSkipVerify = insecureSkipVerify
+	contextConfig.Proxy = proxy
+	return contextConfig
+}
+
+func buildAuthConfig() *extensions.AuthConfig {
+	auth := &extensions.AuthConfig{
+		Token:            authToken,
+		TokenFile:        authTokenFile,
+		CredentialHelper: credentialHelper,
+	}
+	if oauth2Flow != "" {
+		auth.OAuth2 = &extensions.OAuth2Config{
+			Flow:          oauth2Flow,
+			TokenURL:      oauth2TokenURL,
+			DeviceAuthURL: oauth2DeviceAuthURL,
+			ClientID:      oauth2ClientID,
+			ClientSecret:  oauth2ClientSecret,
+			Scopes:        oauth2Scopes,
+		}
+	}
+	if auth.Token == "" && auth.TokenFile == "" && auth.OAuth2 == nil && auth.CredentialHelper == "" {
+		return nil
+	}
+	return auth
+}
+
+// mergeContextConfig returns existing with the server URL and the settings of
+// the flags passed to init, the settings init can not set are kept
+func mergeContextConfig(existing *extensions.ContextConfig, serverURL string, flags *pflag.FlagSet) *extensions.ContextConfig {
+	contextConfig := *existing
+	contextConfig.ServerURL = serverURL
+	setString := func(name string, field *string, value string) {
+		if flags.Changed(name) {
+			*field = value
+		}
+	}
+	setString("ca-file", &contextConfig.CAFile, caFile)
+	setString("cert-file", &contextConfig.CertFile, certFile)
+	setString("key-file", &contextConfig.KeyFile, keyFile)
+	setString("server-name", &contextConfig.ServerName, serverName)
+	setString("proxy", &contextConfig.Proxy, proxy)
+	if flags.Changed("insecure-skip-verify") {
+		contextConfig.InsecureSkipVerify = insecureSkipVerify
+	}
+
+	for _, name := range []string{"auth-token", "auth-token-file", "credential-helper", "oauth2-flow"} {
+		if flags.Changed(name) {
+			contextConfig.Auth = buildAuthConfig()
+			return &contextConfig
+		}
+	}
+	if existing.Auth != nil && existing.Auth.OAuth2 != nil {
+		auth := *existing.Auth
+		oauth2 := *existing.Auth.OAuth2
+		setString("oauth2-token-url", &oauth2.TokenURL, oauth2TokenURL)
+		setString("oauth2-device-auth-url", &oauth2.DeviceAuthURL, oauth2DeviceAuthURL)
+		setString("oauth2-client-id", &oauth2.ClientID, oauth2ClientID)
+		setString("oauth2-client-secret", &oauth2.ClientSecret, oauth2ClientSecret)
+		if flags.Changed("oauth2-scopes") {
+			oauth2.Scopes = oauth2Scopes
+		}
+		for _, name := range []string{"oauth2-token-url", "oauth2-device-auth-url", "oauth2-client-id", "oauth2-client-secret", "oauth2-scopes"} {
+			if flags.Changed(name) {
+				// the cached token was issued to the previous client
+				oauth2.CachedToken = nil
+			}
+		}
+		auth.OAuth2 = &oauth2
+		contextConfig.Auth = &auth
+	}
+	return &contextConfig
 }
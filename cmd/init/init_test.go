@@ -0,0 +1,185 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package init
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/mocks"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const configFile = `version: 1
+currentContext: prod
+contexts:
+  prod:
+    serverUrl: https://maestro.example.com
+    timeout: 30s
+    auth:
+      oauth2:
+        flow: client-credentials
+        tokenUrl: https://auth.example.com/token
+        clientId: maestro-cli
+        clientSecret: secret
+        cachedToken:
+          accessToken: cached-token
+          tokenType: Bearer
+    retry:
+      maxAttempts: 3
+    defaults:
+      game: zooba
+      output: wide
+`
+
+// expectConfigRead makes fs return content as the config file.
+func expectConfigRead(fs *mocks.MockFileSystem, content string) {
+	fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(content), nil)
+	fs.EXPECT().IsNotExist(nil).Return(false)
+}
+
+// expectConfigWrite makes fs write the config file into memory, the returned
+// function reads back the written config.
+func expectConfigWrite(t *testing.T, fs *mocks.MockFileSystem) func() *extensions.Config {
+	memFs := afero.NewMemMapFs()
+	file, err := memFs.Create("config.yaml")
+	require.NoError(t, err)
+
+	fs.EXPECT().MkdirAll(gomock.Any(), extensions.ConfigDirPerm).Return(nil)
+	fs.EXPECT().OpenFile(gomock.Any(), extensions.ConfigFileFlag, extensions.ConfigFilePerm).Return(file, nil)
+
+	return func() *extensions.Config {
+		content, err := afero.ReadFile(memFs, "config.yaml")
+		require.NoError(t, err)
+		config := &extensions.Config{}
+		require.NoError(t, yaml.Unmarshal(content, config))
+		return config
+	}
+}
+
+// setFlags passes flags to init until the end of the test
+func setFlags(t *testing.T, flags map[string]string) {
+	for name, value := range flags {
+		require.NoError(t, Cmd.Flags().Set(name, value))
+	}
+	t.Cleanup(func() {
+		for name := range flags {
+			flag := Cmd.Flags().Lookup(name)
+			require.NoError(t, flag.Value.Set(flag.DefValue))
+			flag.Changed = false
+		}
+	})
+}
+
+func TestInitAction(t *testing.T) {
+	t.Run("fails when not enough args", func(t *testing.T) {
+		err := validateArgs(nil, []string{"prod"})
+
+		require.EqualError(t, err, "missing arg with maestro server URL")
+	})
+
+	t.Run("creates the context", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+		written := expectConfigWrite(t, fs)
+		setFlags(t, map[string]string{"auth-token": "token"})
+
+		out := new(bytes.Buffer)
+		err := NewInit(fs, out).run(Cmd, []string{"local", "http://localhost:8080"})
+
+		require.NoError(t, err)
+		require.Equal(t, "Configuration created\n", out.String())
+		config := written()
+		require.Equal(t, "prod", config.CurrentContext)
+		require.Equal(t, &extensions.ContextConfig{ServerURL: "http://localhost:8080", Auth: &extensions.AuthConfig{Token: "token"}}, config.Contexts["local"])
+	})
+
+	t.Run("keeps the settings of the context it re-initialises", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+		written := expectConfigWrite(t, fs)
+		setFlags(t, map[string]string{"insecure-skip-verify": "true", "server-name": "maestro.internal"})
+
+		out := new(bytes.Buffer)
+		err := NewInit(fs, out).run(Cmd, []string{"prod", "https://maestro.example.com:8443"})
+
+		require.NoError(t, err)
+		require.Equal(t, "Configuration updated\n", out.String())
+		prod := written().Contexts["prod"]
+		require.Equal(t, "https://maestro.example.com:8443", prod.ServerURL)
+		require.Equal(t, "maestro.internal", prod.ServerName)
+		require.True(t, prod.InsecureSkipVerify)
+		require.Equal(t, &extensions.Defaults{Game: "zooba", Output: "wide"}, prod.Defaults)
+		require.Equal(t, 3, prod.Retry.MaxAttempts)
+		require.Equal(t, "secret", prod.Auth.OAuth2.ClientSecret)
+		require.Equal(t, "cached-token", prod.Auth.OAuth2.CachedToken.AccessToken)
+	})
+
+	t.Run("drops the cached token when the oauth2 client changes", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+		written := expectConfigWrite(t, fs)
+		setFlags(t, map[string]string{"oauth2-client-id": "other-client"})
+
+		out := new(bytes.Buffer)
+		err := NewInit(fs, out).run(Cmd, []string{"prod", "https://maestro.example.com"})
+
+		require.NoError(t, err)
+		require.Equal(t, "Configuration updated\nRun maestro-cli login prod to obtain an auth token\n", out.String())
+		prod := written().Contexts["prod"]
+		require.Equal(t, "other-client", prod.Auth.OAuth2.ClientID)
+		require.Equal(t, "secret", prod.Auth.OAuth2.ClientSecret)
+		require.Nil(t, prod.Auth.OAuth2.CachedToken)
+		require.Equal(t, "zooba", prod.Defaults.Game)
+	})
+
+	t.Run("replaces the auth method", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+		written := expectConfigWrite(t, fs)
+		setFlags(t, map[string]string{"auth-token-file": "/var/run/secrets/maestro-token"})
+
+		err := NewInit(fs, new(bytes.Buffer)).run(Cmd, []string{"prod", "https://maestro.example.com"})
+
+		require.NoError(t, err)
+		prod := written().Contexts["prod"]
+		require.Equal(t, &extensions.AuthConfig{TokenFile: "/var/run/secrets/maestro-token"}, prod.Auth)
+		require.Equal(t, "zooba", prod.Defaults.Game)
+	})
+
+	t.Run("fails when the merged context is invalid", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		expectConfigRead(fs, configFile)
+		setFlags(t, map[string]string{"cert-file": "client.pem"})
+
+		err := NewInit(fs, new(bytes.Buffer)).run(Cmd, []string{"prod", "https://maestro.example.com"})
+
+		require.EqualError(t, err, "certFile and keyFile must be set together")
+		require.Equal(t, common.ExitValidation, common.ExitCode(err))
+	})
+}
@@ -0,0 +1,87 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package login
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/common"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/interfaces"
+)
+
+// Cmd represents the login command
+var Cmd = &cobra.Command{
+	Use:     "login",
+	Short:   "Obtains an auth token for a context",
+	Example: "maestro-cli login <context_name>",
+	Long:    "Runs the OAuth2 flow configured for the context and caches the token in ~/.maestro/config.yaml. Defaults to the current context.",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewLogin(extensions.NewFileSystem(), os.Stdout).run(cmd, args)
+	},
+}
+
+type Login struct {
+	fs  interfaces.FileSystem
+	out io.Writer
+}
+
+func NewLogin(fs interfaces.FileSystem, out io.Writer) *Login {
+	return &Login{
+		fs:  fs,
+		out: out,
+	}
+}
+
+func (l *Login) run(cmd *cobra.Command, args []string) error {
+	config, err := extensions.ReadConfig(l.fs)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var contextName string
+	if len(args) > 0 {
+		contextName = args[0]
+	} else {
+		contextName, err = common.ContextName(config)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = config.CheckContext(contextName)
+	if err != nil {
+		return err
+	}
+	contextConfig, _ := config.Context(contextName)
+	if contextConfig.Auth == nil || contextConfig.Auth.OAuth2 == nil {
+		return fmt.Errorf("context %q has no oauth2 auth configured, there is nothing to login", contextName)
+	}
+
+	token, err := extensions.NewOAuth2TokenSource(contextConfig.Auth.OAuth2, nil).Login(common.CommandContext(cmd), l.out)
+	if err != nil {
+		return fmt.Errorf("error logging in: %w", err)
+	}
+
+	err = config.Write(l.fs)
+	if err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+
+	if token.Expiry.IsZero() {
+		fmt.Fprintf(l.out, "Logged in to context %q\n", contextName)
+	} else {
+		fmt.Fprintf(l.out, "Logged in to context %q, token expires at %s\n", contextName, token.Expiry.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package login
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+func TestLoginAction(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.Form.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client","error_description":"bad secret"}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"the-token","token_type":"bearer","expires_in":3600}`)
+	}))
+	defer tokenServer.Close()
+
+	configFile := func(secret string) string {
+		return fmt.Sprintf(`version: 1
+currentContext: prod
+contexts:
+  prod:
+    serverUrl: https://maestro.example.com
+    auth:
+      oauth2:
+        flow: client-credentials
+        tokenUrl: %s
+        clientId: maestro-cli
+        clientSecret: %s
+  local:
+    serverUrl: http://localhost:8080
+`, tokenServer.URL, secret)
+	}
+
+	t.Run("with success", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile("secret")), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		memFs := afero.NewMemMapFs()
+		file, err := memFs.Create("config.yaml")
+		require.NoError(t, err)
+		fs.EXPECT().MkdirAll(gomock.Any(), extensions.ConfigDirPerm).Return(nil)
+		fs.EXPECT().OpenFile(gomock.Any(), extensions.ConfigFileFlag, extensions.ConfigFilePerm).Return(file, nil)
+
+		out := new(bytes.Buffer)
+		err = NewLogin(fs, out).run(nil, []string{"prod"})
+
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Logged in to context \"prod\", token expires at")
+
+		written, err := afero.ReadFile(memFs, "config.yaml")
+		require.NoError(t, err)
+		require.Contains(t, string(written), "accessToken: the-token")
+	})
+
+	t.Run("fails when the token endpoint rejects the client", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile("wrong")), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		err := NewLogin(fs, new(bytes.Buffer)).run(nil, []string{"prod"})
+
+		require.Error(t, err)
+		require.Equal(t, "error logging in: oauth2 error invalid_client: bad secret", err.Error())
+	})
+
+	t.Run("fails when context has no oauth2 configured", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(configFile("secret")), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		err := NewLogin(fs, new(bytes.Buffer)).run(nil, []string{"local"})
+
+		require.Error(t, err)
+		require.Equal(t, "context \"local\" has no oauth2 auth configured, there is nothing to login", err.Error())
+	})
+}
@@ -10,15 +10,12 @@ package remove
 import (
 	"errors"
 	"fmt"
-	"net/http"
 	"strconv"
 
-	"google.golang.org/protobuf/encoding/protojson"
-
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 )
@@ -36,56 +33,55 @@ var removeRoomsCmd = &cobra.Command{
 			return err
 		}
 
-		return NewRemoveRooms(client, config).run(cmd, args)
+		err = common.Confirm(config, fmt.Sprintf("Remove %s rooms from scheduler %s", args[1], args[0]))
+		if err != nil {
+			return err
+		}
+
+		return NewRemoveRooms(client, config, common.GetWaitParameters(cmd, config)).run(cmd, args)
 	},
 }
 
 type RemoveRooms struct {
-	client interfaces.Client
-	config *extensions.Config
+	client maestro.Client
+	config *extensions.ContextConfig
+	wait   *common.WaitParameters
+}
+
+func init() {
+	common.AddWaitFlags(removeRoomsCmd)
 }
 
-func NewRemoveRooms(client interfaces.Client, config *extensions.Config) *RemoveRooms {
+func NewRemoveRooms(client maestro.Client, config *extensions.ContextConfig, wait *common.WaitParameters) *RemoveRooms {
 	return &RemoveRooms{
 		client: client,
 		config: config,
+		wait:   wait,
 	}
 }
 
-func (a *RemoveRooms) run(_ *cobra.Command, args []string) error {
+func (a *RemoveRooms) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
 
 	logger := common.GetLogger()
 	schedulerName := args[0]
 	roomsAmount, _ := strconv.ParseInt(args[1], 10, 32)
 
-	request := v1.RemoveRoomsRequest{
-		Amount: int32(roomsAmount),
-	}
-
-	serializedRequest, err := protojson.Marshal(&request)
-	if err != nil {
-		return fmt.Errorf("error parsing request to json: %w", err)
+	request := &v1.RemoveRoomsRequest{
+		SchedulerName: schedulerName,
+		Amount:        int32(roomsAmount),
 	}
 
 	logger.Debug("removing rooms from scheduler: " + schedulerName)
 
-	url := fmt.Sprintf("%s/schedulers/%s/remove-rooms", a.config.ServerURL, schedulerName)
-	body, status, err := a.client.Post(url, string(serializedRequest))
+	response, err := a.client.RemoveRooms(ctx, request)
 	if err != nil {
-		return fmt.Errorf("error on post request: %w", err)
-	}
-	if status != http.StatusOK {
-		return fmt.Errorf("remove rooms response not ok, status: %s, body: %s", http.StatusText(status), string(body))
-	}
-	var response v1.RemoveRoomsResponse
-	err = protojson.Unmarshal(body, &response)
-	if err != nil {
-		return fmt.Errorf("error deserializing remove rooms response, details: %w", err)
+		return err
 	}
 
 	logger.Info("Successfully executed remove rooms, operation id: " + response.OperationId)
 
-	return nil
+	return common.WaitOperation(ctx, a.client, a.wait, schedulerName, response.OperationId)
 }
 
 func validateArgs(_ *cobra.Command, args []string) error {
@@ -10,16 +10,19 @@ package remove
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 )
 
 func TestRemoveRoomsAction(t *testing.T) {
 
-	config := &extensions.Config{
+	config := &extensions.ContextConfig{
 		ServerURL: "http://localhost:8080",
 	}
 
@@ -55,14 +58,80 @@ func TestRemoveRoomsAction(t *testing.T) {
 
 		client := mocks.NewMockClient(mockCtrl)
 
-		client.EXPECT().Post(config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").
 			Return([]byte("{\"operationId\": \"abc\"}"), 200, nil)
 
-		err := NewRemoveRooms(client, config).run(nil, []string{"scheduler", "10"})
+		err := NewRemoveRooms(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler", "10"})
 
 		require.NoError(t, err)
 	})
 
+	t.Run("waits for the operation to finish", func(t *testing.T) {
+		common.OperationPollInterval = 0
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+
+		operationURL := config.ServerURL + "/schedulers/scheduler/operations/abc"
+		gomock.InOrder(
+			client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").
+				Return([]byte("{\"operationId\": \"abc\"}"), 200, nil),
+			client.EXPECT().Get(gomock.Any(), operationURL, "").Return([]byte("{\"operation\": {\"status\": \"in_progress\"}}"), 200, nil),
+			client.EXPECT().Get(gomock.Any(), operationURL, "").Return([]byte("{\"operation\": {\"status\": \"finished\"}}"), 200, nil),
+		)
+
+		err := NewRemoveRooms(maestro.NewClient(client, config.ServerURL), config, &common.WaitParameters{Wait: true, Timeout: time.Minute}).run(nil, []string{"scheduler", "10"})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("replays a cassette", func(t *testing.T) {
+		common.OperationPollInterval = 0
+		client, err := extensions.NewReplayer("fixtures/remove-rooms-wait.yaml", extensions.DefaultRedactFields)
+		require.NoError(t, err)
+
+		err = NewRemoveRooms(maestro.NewClient(client, config.ServerURL), config, &common.WaitParameters{Wait: true, Timeout: time.Minute}).run(nil, []string{"scheduler", "10"})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("fails when the waited operation ends with error", func(t *testing.T) {
+		common.OperationPollInterval = 0
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").
+			Return([]byte("{\"operationId\": \"abc\"}"), 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/scheduler/operations/abc", "").
+			Return([]byte("{\"operation\": {\"status\": \"error\"}}"), 200, nil)
+
+		err := NewRemoveRooms(maestro.NewClient(client, config.ServerURL), config, &common.WaitParameters{Wait: true, Timeout: time.Minute}).run(nil, []string{"scheduler", "10"})
+
+		require.Error(t, err)
+		require.Equal(t, "operation abc ended with status error", err.Error())
+	})
+
+	t.Run("fails when the operation does not finish before the wait timeout", func(t *testing.T) {
+		common.OperationPollInterval = 0
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		client := mocks.NewMockClient(mockCtrl)
+
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").
+			Return([]byte("{\"operationId\": \"abc\"}"), 200, nil)
+		client.EXPECT().Get(gomock.Any(), config.ServerURL+"/schedulers/scheduler/operations/abc", "").
+			Return([]byte("{\"operation\": {\"status\": \"pending\"}}"), 200, nil)
+
+		err := NewRemoveRooms(maestro.NewClient(client, config.ServerURL), config, &common.WaitParameters{Wait: true}).run(nil, []string{"scheduler", "10"})
+
+		require.Error(t, err)
+		require.Equal(t, "timed out after 0s waiting for operation abc, last status: pending", err.Error())
+	})
+
 	t.Run("fails when response deserialization fails", func(t *testing.T) {
 
 		mockCtrl := gomock.NewController(t)
@@ -70,12 +139,12 @@ func TestRemoveRoomsAction(t *testing.T) {
 
 		client := mocks.NewMockClient(mockCtrl)
 
-		client.EXPECT().Post(config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").Return([]byte(""), 200, nil)
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").Return([]byte(""), 200, nil)
 
-		err := NewRemoveRooms(client, config).run(nil, []string{"scheduler", "10"})
+		err := NewRemoveRooms(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler", "10"})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "error deserializing remove rooms response")
+		require.Contains(t, err.Error(), "error parsing response body of remove rooms")
 	})
 
 	t.Run("fails when HTTP request fails", func(t *testing.T) {
@@ -85,12 +154,12 @@ func TestRemoveRoomsAction(t *testing.T) {
 
 		client := mocks.NewMockClient(mockCtrl)
 
-		client.EXPECT().Post(config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").Return([]byte(""), 0, fmt.Errorf("tcp connection failed"))
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").Return([]byte(""), 0, fmt.Errorf("tcp connection failed"))
 
-		err := NewRemoveRooms(client, config).run(nil, []string{"scheduler", "10"})
+		err := NewRemoveRooms(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler", "10"})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "error on post request: tcp connection failed")
+		require.Contains(t, err.Error(), "error on POST request: tcp connection failed")
 	})
 
 	t.Run("fails when maestro API fails", func(t *testing.T) {
@@ -100,11 +169,11 @@ func TestRemoveRoomsAction(t *testing.T) {
 
 		client := mocks.NewMockClient(mockCtrl)
 
-		client.EXPECT().Post(config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").Return([]byte(""), 404, nil)
+		client.EXPECT().Post(gomock.Any(), config.ServerURL+"/schedulers/scheduler/remove-rooms", "{\"amount\":10}").Return([]byte(""), 404, nil)
 
-		err := NewRemoveRooms(client, config).run(nil, []string{"scheduler", "10"})
+		err := NewRemoveRooms(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler", "10"})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "remove rooms response not ok, status: Not Found, body: ")
+		require.Contains(t, err.Error(), "remove rooms failed with status Not Found")
 	})
 }
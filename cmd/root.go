@@ -8,17 +8,26 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/topfreegames/maestro-cli/cmd/remove"
 
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/cmd/add"
 	"github.com/topfreegames/maestro-cli/cmd/cancel"
+	configPkg "github.com/topfreegames/maestro-cli/cmd/config"
+	contextPkg "github.com/topfreegames/maestro-cli/cmd/context"
 	"github.com/topfreegames/maestro-cli/cmd/create"
+	"github.com/topfreegames/maestro-cli/cmd/doctor"
 	"github.com/topfreegames/maestro-cli/cmd/get"
 	initPkg "github.com/topfreegames/maestro-cli/cmd/init"
+	"github.com/topfreegames/maestro-cli/cmd/login"
 	"github.com/topfreegames/maestro-cli/cmd/update"
 	"github.com/topfreegames/maestro-cli/cmd/version"
 	"github.com/topfreegames/maestro-cli/common"
@@ -29,25 +38,78 @@ var RootCmd = &cobra.Command{
 	Use:   "maestro-cli",
 	Short: "maestro-cli calls maestro api routes",
 	Long:  `Use maestro-cli to control game rooms schedulers on Kubernetes.`,
+	// Execute prints the error once, with a usage hint on validation errors
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return common.SetupLogger()
+	},
 }
 
-// Execute runs RootCmd to initialize maestro CLI application
+// Execute runs RootCmd to initialize maestro CLI application, requests in
+// flight are cancelled on SIGINT or SIGTERM. It exits with the code of the
+// error, see common.ExitCode.
 func Execute(cmd *cobra.Command) {
-	if err := cmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(-1)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	markValidationErrors(cmd)
+	c, err := cmd.ExecuteContextC(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		var validationErr *common.ValidationError
+		if errors.As(err, &validationErr) {
+			fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", c.CommandPath())
+		}
+		os.Exit(common.ExitCode(err))
+	}
+}
+
+// markValidationErrors makes the args and flags errors of every command
+// validation errors, so they exit with common.ExitValidation
+func markValidationErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return common.NewValidationError(err)
+	})
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(c *cobra.Command, a []string) error {
+			err := args(c, a)
+			if err != nil {
+				return common.NewValidationError(err)
+			}
+			return nil
+		}
+	}
+	for _, child := range cmd.Commands() {
+		markValidationErrors(child)
 	}
 }
 
 func init() {
-	RootCmd.PersistentFlags().IntVarP(
-		&common.Verbose, "verbose", "v", -1,
-		"Verbosity level => v0: Error, v1=Warning, v2=Info, v3=Debug",
-	)
-	RootCmd.PersistentFlags().StringVarP(&common.Context, "context", "c", "prod", "Maestro context, use it to manage different maestro clusters.")
+	RootCmd.PersistentFlags().StringVar(&common.LogLevel, "log-level", "", "Minimum level of the logs written to stderr, one of "+strings.Join(common.LogLevels, ", ")+". Defaults to info.")
+	RootCmd.PersistentFlags().StringVar(&common.LogFormat, "log-format", common.LogFormatText, "Format of the logs, one of "+strings.Join(common.LogFormats, ", ")+".")
+	RootCmd.PersistentFlags().StringVar(&common.LogFile, "log-file", "", "Appends the logs to a file instead of writing them to stderr.")
+	RootCmd.PersistentFlags().BoolVarP(&common.Quiet, "quiet", "q", false, "Only logs errors.")
+	RootCmd.PersistentFlags().IntVarP(&common.Verbose, "verbose", "v", -1, "Verbosity level => v0: Error, v1=Warning, v2=Info, v3=Debug")
+	_ = RootCmd.PersistentFlags().MarkDeprecated("verbose", "use --log-level error|warn|info|debug")
+	RootCmd.PersistentFlags().StringVarP(&common.Context, "context", "c", "", "Maestro context, use it to manage different maestro clusters. Overrides MAESTRO_CONTEXT and the current context.")
+	RootCmd.PersistentFlags().StringVar(&common.ServerURL, "server", "", "Maestro server URL. Overrides MAESTRO_SERVER_URL and the context server URL.")
+	RootCmd.PersistentFlags().StringVar(&common.Token, "token", "", "Token sent on the Authorization header. Overrides MAESTRO_TOKEN and the context auth.")
+	RootCmd.PersistentFlags().DurationVar(&common.Timeout, "timeout", 0, "Deadline of each request, e.g. 30s. Overrides MAESTRO_TIMEOUT and the context timeout.")
+	RootCmd.PersistentFlags().IntVar(&common.RetryMaxAttempts, "retry-max-attempts", 0, "Attempts made by idempotent requests failed by transient errors, 1 disables retries. Overrides the context retry.maxAttempts.")
+	RootCmd.PersistentFlags().DurationVar(&common.RetryMaxElapsedTime, "retry-max-elapsed-time", 0, "How long idempotent requests are retried. Overrides the context retry.maxElapsedTime.")
+	RootCmd.PersistentFlags().StringVar(&common.Record, "record", "", "Appends every request and response to a cassette file, replay it with --replay.")
+	RootCmd.PersistentFlags().StringVar(&common.Replay, "replay", "", "Responds with the interactions of a cassette file recorded by --record instead of sending requests, failing on requests it does not have.")
+	RootCmd.PersistentFlags().BoolVar(&common.Trace, "trace", false, "Writes every request and response to stderr, with the Authorization header and secret fields redacted.")
+	RootCmd.PersistentFlags().BoolVar(&common.TraceCurl, "trace-curl", false, "Like --trace, also writing the curl command of every request.")
+	RootCmd.PersistentFlags().BoolVarP(&common.AssumeYes, "yes", "y", false, "Skips the confirmation asked by mutating commands on contexts with defaults.confirm set.")
 	RootCmd.AddCommand(add.Cmd)
 	RootCmd.AddCommand(remove.Cmd)
 	RootCmd.AddCommand(initPkg.Cmd)
+	RootCmd.AddCommand(contextPkg.Cmd)
+	RootCmd.AddCommand(login.Cmd)
+	RootCmd.AddCommand(configPkg.Cmd)
+	RootCmd.AddCommand(doctor.Cmd)
 	RootCmd.AddCommand(create.Cmd)
 	RootCmd.AddCommand(cancel.Cmd)
 	RootCmd.AddCommand(version.Cmd)
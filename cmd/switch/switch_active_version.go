@@ -3,15 +3,12 @@ package _switch
 import (
 	"errors"
 	"fmt"
-	"net/http"
-
-	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/Masterminds/semver"
 	"github.com/spf13/cobra"
 	"github.com/topfreegames/maestro-cli/common"
 	"github.com/topfreegames/maestro-cli/extensions"
-	"github.com/topfreegames/maestro-cli/interfaces"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 	v1 "github.com/topfreegames/maestro/pkg/api/v1"
 )
 
@@ -28,19 +25,30 @@ var switchActiveVersionCmd = &cobra.Command{
 			return err
 		}
 
-		return NewSwitchActiveVersion(client, config).run(cmd, args)
+		err = common.Confirm(config, fmt.Sprintf("Switch scheduler %s active version to %s", args[0], args[1]))
+		if err != nil {
+			return err
+		}
+
+		return NewSwitchActiveVersion(client, config, common.GetWaitParameters(cmd, config)).run(cmd, args)
 	},
 }
 
 type SwitchActiveVersion struct {
-	client interfaces.Client
-	config *extensions.Config
+	client maestro.Client
+	config *extensions.ContextConfig
+	wait   *common.WaitParameters
+}
+
+func init() {
+	common.AddWaitFlags(switchActiveVersionCmd)
 }
 
-func NewSwitchActiveVersion(client interfaces.Client, config *extensions.Config) *SwitchActiveVersion {
+func NewSwitchActiveVersion(client maestro.Client, config *extensions.ContextConfig, wait *common.WaitParameters) *SwitchActiveVersion {
 	return &SwitchActiveVersion{
 		client: client,
 		config: config,
+		wait:   wait,
 	}
 }
 
@@ -67,35 +75,23 @@ func isVersionValid(version string) error {
 	return nil
 }
 
-func (a *SwitchActiveVersion) run(_ *cobra.Command, args []string) error {
+func (a *SwitchActiveVersion) run(cmd *cobra.Command, args []string) error {
+	ctx := common.CommandContext(cmd)
 	logger := common.GetLogger()
 	schedulerName := args[0]
 	targetVersion := args[0]
 
-	request := v1.SwitchActiveVersionRequest{
-		Version: targetVersion,
-	}
-
-	serializedRequest, err := common.Marshaller.Marshal(&request)
-	if err != nil {
-		return fmt.Errorf("error parsing request to json: %w", err)
+	request := &v1.SwitchActiveVersionRequest{
+		SchedulerName: schedulerName,
+		Version:       targetVersion,
 	}
 
 	logger.Debug("switch active version to scheduler: " + schedulerName)
 
-	url := fmt.Sprintf("%s/schedulers/%s", a.config.ServerURL, schedulerName)
-	body, status, err := a.client.Put(url, string(serializedRequest))
+	response, err := a.client.SwitchActiveVersion(ctx, request)
 	if err != nil {
-		return fmt.Errorf("error on put request: %w", err)
-	}
-	if status != http.StatusOK {
-		return fmt.Errorf("switch active version response not ok, status: %s, body: %s", http.StatusText(status), string(body))
-	}
-	var response v1.SwitchActiveVersionResponse
-	err = protojson.Unmarshal(body, &response)
-	if err != nil {
-		return fmt.Errorf("error deserializing switch active version response, details: %w", err)
+		return err
 	}
 	logger.Info("Successfully executed switch active version operation, operation id: " + response.OperationId)
-	return nil
+	return common.WaitOperation(ctx, a.client, a.wait, schedulerName, response.OperationId)
 }
@@ -10,12 +10,13 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/mocks"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func TestSwitchSchedulerVersion(t *testing.T) {
 
-	config := &extensions.Config{
+	config := &extensions.ContextConfig{
 		ServerURL: "http://localhost:8080",
 	}
 
@@ -50,9 +51,9 @@ func TestSwitchSchedulerVersion(t *testing.T) {
 
 		require.NoError(t, err)
 
-		client.EXPECT().Put(config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return([]byte(expectedResponse), 200, nil)
+		client.EXPECT().Put(gomock.Any(), config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return([]byte(expectedResponse), 200, nil)
 
-		err = NewSwitchActiveVersion(client, config).run(nil, []string{"scheduler-name", "v1.0.0"})
+		err = NewSwitchActiveVersion(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler-name", "v1.0.0"})
 
 		require.NoError(t, err)
 	})
@@ -63,12 +64,12 @@ func TestSwitchSchedulerVersion(t *testing.T) {
 
 		client := mocks.NewMockClient(mockCtrl)
 
-		client.EXPECT().Put(config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return([]byte(""), 404, fmt.Errorf("tcp connection failed"))
+		client.EXPECT().Put(gomock.Any(), config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return([]byte(""), 404, fmt.Errorf("tcp connection failed"))
 
-		err := NewSwitchActiveVersion(client, config).run(nil, []string{"scheduler-name", "v1.0.0"})
+		err := NewSwitchActiveVersion(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler-name", "v1.0.0"})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "error on put request: tcp connection failed")
+		require.Contains(t, err.Error(), "error on PUT request: tcp connection failed")
 	})
 
 	t.Run("fails when maestro API fails", func(t *testing.T) {
@@ -77,11 +78,11 @@ func TestSwitchSchedulerVersion(t *testing.T) {
 
 		client := mocks.NewMockClient(mockCtrl)
 
-		client.EXPECT().Put(config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return([]byte(""), 404, nil)
+		client.EXPECT().Put(gomock.Any(), config.ServerURL+"/schedulers/scheduler-name", gomock.Any()).Return([]byte(""), 404, nil)
 
-		err := NewSwitchActiveVersion(client, config).run(nil, []string{"scheduler-name", "v1.0.0"})
+		err := NewSwitchActiveVersion(maestro.NewClient(client, config.ServerURL), config, nil).run(nil, []string{"scheduler-name", "v1.0.0"})
 
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "switch active version response not ok, status: Not Found, body: ")
+		require.Contains(t, err.Error(), "switch active version failed with status Not Found")
 	})
 }
@@ -9,75 +9,177 @@ package common
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/topfreegames/maestro-cli/extensions"
 	"github.com/topfreegames/maestro-cli/interfaces"
-	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
 	yaml "gopkg.in/yaml.v2"
 )
 
-// Verbose determines how verbose maestro will run under
-var Verbose int
+// GetClientAndConfig returns the maestro client and the config of the active
+// context
+func GetClientAndConfig() (maestro.Client, *extensions.ContextConfig, error) {
 
-var Context string
+	resolved, err := ResolveConfig(extensions.NewFileSystem())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting client config: %w", err)
+	}
+
+	maestro.OnUnknownFields(warnUnknownFields)
+	maestro.StrictDecoding, err = strictDecoding()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resolved.Context.GetTransport() == extensions.TransportGRPC {
+		if Record != "" || Replay != "" {
+			return nil, nil, NewValidationError(errors.New("--record and --replay need the http transport"))
+		}
+		conn, err := GetGRPCConn(resolved.ContextName, resolved.Context)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting client: %w", err)
+		}
+		return maestro.NewGRPCClient(conn), resolved.Context, nil
+	}
 
-func GetClientAndConfig() (interfaces.Client, *extensions.Config, error) {
+	client, err := GetClient(resolved.ContextName, resolved.Context)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting client: %w", err)
+	}
 
-	config, err := GetConfig()
+	cassetteClient, err := withCassette(client, resolved.Context.Trace.GetRedactFields())
 	if err != nil {
-		return nil, nil, fmt.Errorf("error getting client config: %w", err)
+		return nil, nil, err
 	}
 
-	client := GetClient(config)
+	return maestro.NewClient(cassetteClient, resolved.Context.BaseURL()), resolved.Context, nil
+}
+
+// EnvStrictDecoding set to true makes the commands fail on response fields
+// unknown to this maestro-cli version, e.g. on CI jobs checking a maestro
+// upgrade
+const EnvStrictDecoding = "MAESTRO_STRICT_DECODING"
 
-	return client, config, nil
+// warnUnknownFields tells that maestro sends fields this maestro-cli version
+// does not know, they are not shown. It is only logged by --log-level debug
+// or -v 3, maestro.Decode calls it once per process.
+func warnUnknownFields(message string, err error) {
+	GetLogger().Sugar().Debugf("maestro responded with fields unknown to this maestro-cli version, upgrade it to see them: %s: %s", message, err)
 }
 
-func GetConfig() (*extensions.Config, error) {
-	filesystem := extensions.NewFileSystem()
-	config, err := extensions.ReadConfig(filesystem, Context)
+// strictDecoding returns the MAESTRO_STRICT_DECODING env, false unless set
+func strictDecoding() (bool, error) {
+	value := os.Getenv(EnvStrictDecoding)
+	if value == "" {
+		return false, nil
+	}
+	strict, err := strconv.ParseBool(value)
 	if err != nil {
-		return nil, errors.New("probably you should login")
+		return false, NewValidationError(fmt.Errorf("bad %s value %q, use true or false", EnvStrictDecoding, value))
 	}
-	return config, nil
+	return strict, nil
 }
 
-func GetClient(config *extensions.Config) *extensions.Client {
-	client := extensions.NewClient(config)
-	return client
+// withCassette decorates client to record or replay the cassette set by
+// --record or --replay, redacting the bodies like the traces
+func withCassette(client interfaces.Client, redactFields []string) (interfaces.Client, error) {
+	switch {
+	case Record != "" && Replay != "":
+		return nil, NewValidationError(errors.New("--record and --replay can not be used together"))
+	case Record != "":
+		return extensions.NewRecorder(client, Record, redactFields), nil
+	case Replay != "":
+		replayer, err := extensions.NewReplayer(Replay, redactFields)
+		if err != nil {
+			return nil, err
+		}
+		return replayer, nil
+	}
+	return client, nil
 }
 
-func GetLogger() *zap.Logger {
-	var ll zapcore.Level
-	switch Verbose {
-	case 0:
-		ll = zap.ErrorLevel
-	case 1:
-		ll = zap.WarnLevel
-	case 3:
-		ll = zap.DebugLevel
-	default:
-		ll = zap.InfoLevel
+// clients and connections are shared by the whole process, so requests
+// reuse the keep-alive connections of the context
+var (
+	clients      = map[string]*extensions.Client{}
+	grpcConns    = map[string]*grpc.ClientConn{}
+	clientsMutex sync.Mutex
+)
+
+// GetClient returns the client of the context, created on the first call
+func GetClient(contextName string, config *extensions.ContextConfig) (*extensions.Client, error) {
+	clientsMutex.Lock()
+	defer clientsMutex.Unlock()
+	if client, ok := clients[contextName]; ok {
+		return client, nil
 	}
 
-	log := zap.NewDevelopmentConfig()
-	log.OutputPaths = []string{"stdout"}
-	log.Level.SetLevel(ll)
-	log.EncoderConfig = zapcore.EncoderConfig{
-		MessageKey: "message",
+	tokenSource, err := GetTokenSource(contextName, config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := extensions.NewClient(config, tokenSource)
+	if err != nil {
+		return nil, err
+	}
+	if Trace || TraceCurl {
+		client.EnableTrace(os.Stderr, TraceCurl)
 	}
+	clients[contextName] = client
+	return client, nil
+}
 
-	logger, _ := log.Build()
+// GetGRPCConn returns the gRPC connection of the context, created on the
+// first call
+func GetGRPCConn(contextName string, config *extensions.ContextConfig) (*grpc.ClientConn, error) {
+	clientsMutex.Lock()
+	defer clientsMutex.Unlock()
+	if conn, ok := grpcConns[contextName]; ok {
+		return conn, nil
+	}
 
-	return logger
+	tokenSource, err := GetTokenSource(contextName, config)
+	if err != nil {
+		return nil, err
+	}
+	var opts []grpc.DialOption
+	if Trace || TraceCurl {
+		opts = append(opts, extensions.WithGRPCTrace(os.Stderr, config.Trace))
+	}
+	conn, err := extensions.NewGRPCConn(config, tokenSource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	grpcConns[contextName] = conn
+	return conn, nil
+}
+
+// GetTokenSource returns the token source of the context, persisting the
+// OAuth2 tokens it obtains
+func GetTokenSource(contextName string, config *extensions.ContextConfig) (extensions.TokenSource, error) {
+	return extensions.NewTokenSource(contextName, config, saveToken(contextName))
+}
+
+// CommandContext returns the context of the command, cancelled when
+// maestro-cli is interrupted
+func CommandContext(cmd *cobra.Command) context.Context {
+	if cmd == nil || cmd.Context() == nil {
+		return context.Background()
+	}
+	return cmd.Context()
 }
 
 func Success(response map[string]interface{}) (string, bool) {
@@ -0,0 +1,69 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestUnknownFields(t *testing.T) {
+	t.Run("warns once about unknown fields on debug", func(t *testing.T) {
+		defer func() { logger = nil }()
+		out := new(bytes.Buffer)
+		logger = NewLogger(out, zapcore.DebugLevel, LogFormatJSON)
+		maestro.OnUnknownFields(warnUnknownFields)
+		defer maestro.OnUnknownFields(nil)
+
+		for i := 0; i < 2; i++ {
+			err := maestro.Decode([]byte(`{"schedulers":[{"name":"scheduler","newField":"value"}]}`), &v1.ListSchedulersResponse{})
+			require.NoError(t, err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 1)
+		require.Contains(t, lines[0], `"level":"debug"`)
+		require.Contains(t, lines[0], "maestro responded with fields unknown to this maestro-cli version")
+	})
+
+	t.Run("does not warn about unknown fields on the default level", func(t *testing.T) {
+		defer func() { logger = nil }()
+		out := new(bytes.Buffer)
+		logger = NewLogger(out, zapcore.InfoLevel, LogFormatJSON)
+
+		warnUnknownFields("maestro.v1.ListSchedulersResponse", errors.New(`unknown field "newField"`))
+
+		require.Empty(t, out.String())
+	})
+
+	t.Run("reads strict decoding from the env", func(t *testing.T) {
+		defer os.Unsetenv(EnvStrictDecoding)
+
+		strict, err := strictDecoding()
+		require.NoError(t, err)
+		require.False(t, strict)
+
+		os.Setenv(EnvStrictDecoding, "true")
+		strict, err = strictDecoding()
+		require.NoError(t, err)
+		require.True(t, strict)
+
+		os.Setenv(EnvStrictDecoding, "yes")
+		_, err = strictDecoding()
+		require.EqualError(t, err, `bad MAESTRO_STRICT_DECODING value "yes", use true or false`)
+		require.Equal(t, ExitValidation, ExitCode(err))
+	})
+}
@@ -0,0 +1,280 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/interfaces"
+)
+
+// Environment variables overriding the context file
+const (
+	EnvContext   = "MAESTRO_CONTEXT"
+	EnvServerURL = "MAESTRO_SERVER_URL"
+	EnvToken     = "MAESTRO_TOKEN"
+	EnvTimeout   = "MAESTRO_TIMEOUT"
+)
+
+// Where a setting value came from, settings are resolved with the precedence
+// flag > env > context file > defaults
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceFile    = "context file"
+	SourceDefault = "default"
+)
+
+var errNoContext = errors.New("no context selected, run maestro-cli init <context> <server_url> to create one or maestro-cli context use <context> to select one")
+
+// Context is the maestro context to use instead of the current one
+var Context string
+
+// ServerURL overrides the server URL of the context
+var ServerURL string
+
+// Token overrides the auth of the context with a static token
+var Token string
+
+// Timeout overrides the request timeout of the context
+var Timeout time.Duration
+
+// RetryMaxAttempts overrides the retry attempts of the context
+var RetryMaxAttempts int
+
+// RetryMaxElapsedTime overrides how long the context retries requests
+var RetryMaxElapsedTime time.Duration
+
+// Record is the cassette file every request and response are appended to
+var Record string
+
+// Replay is the cassette file responses are served from, no request is sent
+var Replay string
+
+// Trace writes every request and response to stderr
+var Trace bool
+
+// TraceCurl writes the curl command of every request to stderr, it implies
+// Trace
+var TraceCurl bool
+
+// Setting is an effective setting and where its value came from
+type Setting struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+// ResolvedConfig is the context settings after applying flags and env
+// overrides
+type ResolvedConfig struct {
+	ContextName string
+	Context     *extensions.ContextConfig
+	Settings    []Setting
+}
+
+// GetConfig returns the resolved settings of the selected context
+func GetConfig() (*extensions.ContextConfig, error) {
+	resolved, err := ResolveConfig(extensions.NewFileSystem())
+	if err != nil {
+		return nil, err
+	}
+	return resolved.Context, nil
+}
+
+// ResolveConfig applies the flags and env overrides on the selected context.
+// A context file is not needed when the server URL is set by flag or env.
+func ResolveConfig(fs interfaces.FileSystem) (*ResolvedConfig, error) {
+	config, err := extensions.ReadConfig(fs)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range config.Migrations {
+		GetLogger().Info(migration)
+	}
+
+	resolved := &ResolvedConfig{Context: &extensions.ContextConfig{}}
+
+	contextName, source := contextName(config)
+	if contextName != "" {
+		err = config.CheckContext(contextName)
+		if err != nil {
+			return nil, err
+		}
+		contextConfig, _ := config.Context(contextName)
+		// overrides must not leak into the config file if it is written back
+		copied := *contextConfig
+		resolved.Context = &copied
+		resolved.ContextName = contextName
+		resolved.add("context", contextName, source)
+	} else {
+		resolved.add("context", "-", SourceDefault)
+	}
+
+	switch {
+	case ServerURL != "":
+		err = validateServerURL("--server", ServerURL)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Context.ServerURL = ServerURL
+		resolved.add("serverUrl", ServerURL, SourceFlag)
+	case os.Getenv(EnvServerURL) != "":
+		err = validateServerURL(EnvServerURL, os.Getenv(EnvServerURL))
+		if err != nil {
+			return nil, err
+		}
+		resolved.Context.ServerURL = os.Getenv(EnvServerURL)
+		resolved.add("serverUrl", resolved.Context.ServerURL, SourceEnv)
+	case resolved.Context.ServerURL != "":
+		resolved.add("serverUrl", resolved.Context.ServerURL, SourceFile)
+	case contextName == "":
+		return nil, fmt.Errorf("%w, or set %s", errNoContext, EnvServerURL)
+	default:
+		return nil, fmt.Errorf("context %q has no server URL, run maestro-cli init %s <server_url> to set it", contextName, contextName)
+	}
+
+	switch {
+	case Token != "":
+		resolved.Context.Auth = &extensions.AuthConfig{Token: Token}
+		resolved.add("auth", describeAuth(resolved.Context.Auth), SourceFlag)
+	case os.Getenv(EnvToken) != "":
+		resolved.Context.Auth = &extensions.AuthConfig{Token: os.Getenv(EnvToken)}
+		resolved.add("auth", describeAuth(resolved.Context.Auth), SourceEnv)
+	case resolved.Context.Auth != nil:
+		resolved.add("auth", describeAuth(resolved.Context.Auth), SourceFile)
+	default:
+		resolved.add("auth", describeAuth(nil), SourceDefault)
+	}
+
+	switch {
+	case Timeout != 0:
+		resolved.Context.Timeout = Timeout
+		resolved.add("timeout", Timeout.String(), SourceFlag)
+	case os.Getenv(EnvTimeout) != "":
+		timeout, err := time.ParseDuration(os.Getenv(EnvTimeout))
+		if err != nil {
+			return nil, NewValidationError(fmt.Errorf("bad %s value, use a duration like 30s or 5m: %w", EnvTimeout, err))
+		}
+		resolved.Context.Timeout = timeout
+		resolved.add("timeout", timeout.String(), SourceEnv)
+	case resolved.Context.Timeout != 0:
+		resolved.add("timeout", resolved.Context.Timeout.String(), SourceFile)
+	default:
+		resolved.Context.Timeout = extensions.DefaultTimeout
+		resolved.add("timeout", extensions.DefaultTimeout.String(), SourceDefault)
+	}
+
+	retry := extensions.RetryConfig{}
+	if resolved.Context.Retry != nil {
+		retry = *resolved.Context.Retry
+	}
+	switch {
+	case RetryMaxAttempts != 0:
+		retry.MaxAttempts = RetryMaxAttempts
+		resolved.add("retry.maxAttempts", strconv.Itoa(retry.MaxAttempts), SourceFlag)
+	case retry.MaxAttempts != 0:
+		resolved.add("retry.maxAttempts", strconv.Itoa(retry.MaxAttempts), SourceFile)
+	default:
+		retry.MaxAttempts = extensions.DefaultRetryMaxAttempts
+		resolved.add("retry.maxAttempts", strconv.Itoa(retry.MaxAttempts), SourceDefault)
+	}
+	switch {
+	case RetryMaxElapsedTime != 0:
+		retry.MaxElapsedTime = RetryMaxElapsedTime
+		resolved.add("retry.maxElapsedTime", retry.MaxElapsedTime.String(), SourceFlag)
+	case retry.MaxElapsedTime != 0:
+		resolved.add("retry.maxElapsedTime", retry.MaxElapsedTime.String(), SourceFile)
+	default:
+		retry.MaxElapsedTime = extensions.DefaultRetryMaxElapsedTime
+		resolved.add("retry.maxElapsedTime", retry.MaxElapsedTime.String(), SourceDefault)
+	}
+	resolved.Context.Retry = &retry
+
+	return resolved, nil
+}
+
+// validateServerURL checks a server URL override like init checks the
+// serverUrl of the context file
+func validateServerURL(name, serverURL string) error {
+	_, err := extensions.ParseServerURL(serverURL)
+	if err != nil {
+		return NewValidationError(fmt.Errorf("bad %s value: %w", name, err))
+	}
+	return nil
+}
+
+func (r *ResolvedConfig) add(name, value, source string) {
+	r.Settings = append(r.Settings, Setting{Name: name, Value: value, Source: source})
+}
+
+// describeAuth describes the auth method without exposing secrets
+func describeAuth(auth *extensions.AuthConfig) string {
+	switch {
+	case auth == nil:
+		return "none"
+	case auth.Token != "":
+		return "token"
+	case auth.TokenFile != "":
+		return "tokenFile " + auth.TokenFile
+	case auth.OAuth2 != nil:
+		return "oauth2 " + auth.OAuth2.Flow
+	case auth.CredentialHelper != "":
+		return "credentialHelper " + auth.CredentialHelper
+	default:
+		return "none"
+	}
+}
+
+// ContextName returns the context selected by the --context flag or the
+// MAESTRO_CONTEXT env, falling back to the current context of config
+func ContextName(config *extensions.Config) (string, error) {
+	name, _ := contextName(config)
+	if name == "" {
+		return "", errNoContext
+	}
+	return name, nil
+}
+
+func contextName(config *extensions.Config) (string, string) {
+	switch {
+	case Context != "":
+		return Context, SourceFlag
+	case os.Getenv(EnvContext) != "":
+		return os.Getenv(EnvContext), SourceEnv
+	default:
+		return config.CurrentContext, SourceFile
+	}
+}
+
+// saveToken caches OAuth2 tokens obtained while running a command, so the
+// next commands do not need to fetch them again
+func saveToken(contextName string) func(*extensions.Token) {
+	return func(token *extensions.Token) {
+		logger := GetLogger()
+		filesystem := extensions.NewFileSystem()
+		config, err := extensions.ReadConfig(filesystem)
+		if err != nil {
+			logger.Warn("could not cache auth token: " + err.Error())
+			return
+		}
+		contextConfig, err := config.Context(contextName)
+		if err != nil || contextConfig.Auth == nil || contextConfig.Auth.OAuth2 == nil {
+			return
+		}
+		contextConfig.Auth.OAuth2.CachedToken = token
+		if err = config.Write(filesystem); err != nil {
+			logger.Warn("could not cache auth token: " + err.Error())
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	"google.golang.org/grpc/codes"
+)
+
+// Exit codes of maestro-cli, scripts can branch on them instead of parsing
+// the error message
+const (
+	// ExitError is the exit code of errors without a specific code
+	ExitError = 1
+	// ExitValidation is the exit code of invalid args, flags or files, no
+	// request is sent to maestro
+	ExitValidation = 2
+	// ExitNotFound is the exit code when the scheduler or operation does not
+	// exist
+	ExitNotFound = 3
+	// ExitAlreadyExists is the exit code when the scheduler or version already
+	// exists
+	ExitAlreadyExists = 4
+	// ExitInvalidArgument is the exit code when maestro rejects the request
+	ExitInvalidArgument = 5
+	// ExitConflict is the exit code when maestro can not apply the request to
+	// the current state, e.g. an operation already running
+	ExitConflict = 6
+	// ExitUnauthenticated is the exit code when maestro rejects the
+	// credentials or a login is required
+	ExitUnauthenticated = 7
+	// ExitUnavailable is the exit code when maestro can not be reached or does
+	// not respond in time
+	ExitUnavailable = 8
+)
+
+// ValidationError is an error found by maestro-cli before sending any request
+type ValidationError struct {
+	Err error
+}
+
+// NewValidationError wraps err as a validation error
+func NewValidationError(err error) error {
+	return &ValidationError{Err: err}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the error returned by a command
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return ExitValidation
+	}
+
+	var apiErr *maestro.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case codes.NotFound:
+			return ExitNotFound
+		case codes.AlreadyExists:
+			return ExitAlreadyExists
+		case codes.InvalidArgument, codes.OutOfRange:
+			return ExitInvalidArgument
+		case codes.FailedPrecondition, codes.Aborted:
+			return ExitConflict
+		case codes.Unauthenticated, codes.PermissionDenied:
+			return ExitUnauthenticated
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+			return ExitUnavailable
+		}
+		return ExitError
+	}
+
+	if errors.Is(err, extensions.ErrLoginRequired) {
+		return ExitUnauthenticated
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return ExitUnavailable
+	}
+	return ExitError
+}
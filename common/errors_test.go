@@ -0,0 +1,55 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+)
+
+func TestExitCode(t *testing.T) {
+	apiError := func(statusCode int, body string) error {
+		return maestro.NewAPIError("create scheduler", statusCode, []byte(body))
+	}
+
+	testCases := []struct {
+		Title    string
+		Err      error
+		Expected int
+	}{
+		{Title: "no error", Err: nil, Expected: 0},
+		{Title: "other errors", Err: errors.New("some error"), Expected: ExitError},
+		{Title: "validation", Err: NewValidationError(errors.New("missing arg")), Expected: ExitValidation},
+		{Title: "wrapped validation", Err: fmt.Errorf("create: %w", NewValidationError(errors.New("bad yaml"))), Expected: ExitValidation},
+		{Title: "not found", Err: apiError(http.StatusNotFound, `{"code":5,"message":"not found"}`), Expected: ExitNotFound},
+		{Title: "already exists", Err: apiError(http.StatusConflict, `{"code":6,"message":"already exists"}`), Expected: ExitAlreadyExists},
+		{Title: "invalid argument", Err: apiError(http.StatusBadRequest, `{"code":3,"message":"invalid"}`), Expected: ExitInvalidArgument},
+		{Title: "conflict", Err: apiError(http.StatusBadRequest, `{"code":9,"message":"operation running"}`), Expected: ExitConflict},
+		{Title: "unauthenticated", Err: apiError(http.StatusUnauthorized, ""), Expected: ExitUnauthenticated},
+		{Title: "permission denied", Err: apiError(http.StatusForbidden, ""), Expected: ExitUnauthenticated},
+		{Title: "login required", Err: fmt.Errorf("error getting token: %w", extensions.ErrLoginRequired), Expected: ExitUnauthenticated},
+		{Title: "unavailable", Err: apiError(http.StatusServiceUnavailable, ""), Expected: ExitUnavailable},
+		{Title: "internal", Err: apiError(http.StatusInternalServerError, `{"code":13,"message":"internal"}`), Expected: ExitError},
+		{Title: "connection refused", Err: fmt.Errorf("error on GET request: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), Expected: ExitUnavailable},
+		{Title: "timeout", Err: fmt.Errorf("error on GET request: %w", context.DeadlineExceeded), Expected: ExitUnavailable},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Title, func(t *testing.T) {
+			require.Equal(t, testCase.Expected, ExitCode(testCase.Err))
+		})
+	}
+}
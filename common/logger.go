@@ -0,0 +1,150 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// LogFormatText logs the messages only, the default
+	LogFormatText = "text"
+	// LogFormatJSON logs a JSON object with the time, level and message of
+	// every entry
+	LogFormatJSON = "json"
+)
+
+// LogFormats are the values accepted by --log-format
+var LogFormats = []string{LogFormatText, LogFormatJSON}
+
+// LogLevels are the values accepted by --log-level, from the least verbose
+var LogLevels = []string{"error", "warn", "info", "debug"}
+
+var (
+	// LogLevel is the minimum level of the logged entries
+	LogLevel string
+	// LogFormat is one of LogFormats
+	LogFormat string
+	// LogFile is the file the logs are appended to instead of stderr
+	LogFile string
+	// Quiet only logs errors
+	Quiet bool
+	// Verbose is the deprecated numeric level of --verbose, 0 for errors up
+	// to 3 for debug, -1 unless set
+	Verbose = -1
+)
+
+// logger is the logger of the process, built by SetupLogger or by the first
+// GetLogger call
+var (
+	logger      *zap.Logger
+	loggerMutex sync.Mutex
+)
+
+// SetupLogger builds the logger of the process from the log flags, it fails
+// on bad flags or when the log file can not be opened
+func SetupLogger() error {
+	level, err := logLevel()
+	if err != nil {
+		return NewValidationError(err)
+	}
+	format := LogFormat
+	if format == "" {
+		format = LogFormatText
+	}
+	if format != LogFormatText && format != LogFormatJSON {
+		return NewValidationError(fmt.Errorf("bad log format %q, use one of %s", LogFormat, strings.Join(LogFormats, ", ")))
+	}
+
+	var out io.Writer = os.Stderr
+	if LogFile != "" {
+		file, err := os.OpenFile(LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("error opening log file: %w", err)
+		}
+		out = file
+	}
+
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	logger = NewLogger(out, level, format)
+	return nil
+}
+
+// GetLogger returns the logger of the process, logging info entries to
+// stderr when SetupLogger was not called
+func GetLogger() *zap.Logger {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	if logger == nil {
+		logger = NewLogger(os.Stderr, zapcore.InfoLevel, LogFormatText)
+	}
+	return logger
+}
+
+// NewLogger returns a logger writing the entries of level or above to out
+// in format
+func NewLogger(out io.Writer, level zapcore.Level, format string) *zap.Logger {
+	var encoder zapcore.Encoder
+	if format == LogFormatJSON {
+		encoder = zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+			TimeKey:        "time",
+			LevelKey:       "level",
+			MessageKey:     "message",
+			LineEnding:     zapcore.DefaultLineEnding,
+			EncodeTime:     zapcore.ISO8601TimeEncoder,
+			EncodeLevel:    zapcore.LowercaseLevelEncoder,
+			EncodeDuration: zapcore.StringDurationEncoder,
+		})
+	} else {
+		encoder = zapcore.NewConsoleEncoder(zapcore.EncoderConfig{
+			MessageKey:     "message",
+			LineEnding:     zapcore.DefaultLineEnding,
+			EncodeDuration: zapcore.StringDurationEncoder,
+		})
+	}
+	return zap.New(zapcore.NewCore(encoder, zapcore.Lock(zapcore.AddSync(out)), level))
+}
+
+// logLevel returns the level of --quiet, --log-level or the deprecated
+// --verbose, info unless set
+func logLevel() (zapcore.Level, error) {
+	name := LogLevel
+	switch {
+	case Quiet && (LogLevel != "" || Verbose >= 0):
+		return 0, errors.New("--quiet can not be used with --log-level or --verbose")
+	case LogLevel != "" && Verbose >= 0:
+		return 0, errors.New("--log-level and --verbose can not be used together")
+	case Quiet:
+		return zapcore.ErrorLevel, nil
+	case Verbose >= 0:
+		if Verbose >= len(LogLevels) {
+			return zapcore.DebugLevel, nil
+		}
+		name = LogLevels[Verbose]
+	case name == "":
+		return zapcore.InfoLevel, nil
+	}
+
+	for _, l := range LogLevels {
+		if name == l {
+			var level zapcore.Level
+			err := level.UnmarshalText([]byte(l))
+			return level, err
+		}
+	}
+	return 0, fmt.Errorf("bad log level %q, use one of %s", LogLevel, strings.Join(LogLevels, ", "))
+}
@@ -0,0 +1,118 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogger(t *testing.T) {
+	resetLogFlags := func() {
+		LogLevel, LogFormat, LogFile, Quiet, Verbose = "", LogFormatText, "", false, -1
+		logger = nil
+	}
+
+	t.Run("logs the messages as text", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		l := NewLogger(out, zapcore.InfoLevel, LogFormatText)
+
+		l.Debug("debug message")
+		l.Info("Successfully created scheduler: scheduler-name")
+		l.Warn("warn message", zap.String("scheduler", "scheduler-name"))
+
+		require.Equal(t, "Successfully created scheduler: scheduler-name\n"+
+			"warn message\t{\"scheduler\": \"scheduler-name\"}\n", out.String())
+	})
+
+	t.Run("logs the entries as json", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		l := NewLogger(out, zapcore.DebugLevel, LogFormatJSON)
+
+		l.Debug("waiting for operation", zap.String("operation", "operation-id"))
+
+		entry := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+		require.Equal(t, "debug", entry["level"])
+		require.Equal(t, "waiting for operation", entry["message"])
+		require.Equal(t, "operation-id", entry["operation"])
+		require.NotEmpty(t, entry["time"])
+	})
+
+	t.Run("builds the logger once from the flags", func(t *testing.T) {
+		defer resetLogFlags()
+		LogFile = filepath.Join(t.TempDir(), "maestro-cli.log")
+		LogFormat = LogFormatJSON
+		LogLevel = "warn"
+
+		require.NoError(t, SetupLogger())
+		require.Same(t, GetLogger(), GetLogger())
+		GetLogger().Info("info message")
+		GetLogger().Warn("warn message")
+
+		bts, err := ioutil.ReadFile(LogFile)
+		require.NoError(t, err)
+		require.NotContains(t, string(bts), "info message")
+		require.Contains(t, string(bts), `"message":"warn message"`)
+	})
+
+	t.Run("maps the levels of the flags", func(t *testing.T) {
+		defer resetLogFlags()
+		for _, testCase := range []struct {
+			LogLevel      string
+			Quiet         bool
+			Verbose       int
+			ExpectedLevel zapcore.Level
+		}{
+			{Verbose: -1, ExpectedLevel: zapcore.InfoLevel},
+			{LogLevel: "debug", Verbose: -1, ExpectedLevel: zapcore.DebugLevel},
+			{LogLevel: "error", Verbose: -1, ExpectedLevel: zapcore.ErrorLevel},
+			{Quiet: true, Verbose: -1, ExpectedLevel: zapcore.ErrorLevel},
+			{Verbose: 0, ExpectedLevel: zapcore.ErrorLevel},
+			{Verbose: 1, ExpectedLevel: zapcore.WarnLevel},
+			{Verbose: 3, ExpectedLevel: zapcore.DebugLevel},
+		} {
+			LogLevel, Quiet, Verbose = testCase.LogLevel, testCase.Quiet, testCase.Verbose
+
+			require.NoError(t, SetupLogger())
+
+			require.True(t, GetLogger().Core().Enabled(testCase.ExpectedLevel), testCase)
+			require.False(t, GetLogger().Core().Enabled(testCase.ExpectedLevel-1), testCase)
+		}
+	})
+
+	t.Run("fails on bad flags", func(t *testing.T) {
+		defer resetLogFlags()
+		for _, testCase := range []struct {
+			LogLevel      string
+			LogFormat     string
+			Quiet         bool
+			Verbose       int
+			ExpectedError string
+		}{
+			{LogLevel: "trace", Verbose: -1, ExpectedError: `bad log level "trace", use one of error, warn, info, debug`},
+			{LogFormat: "logfmt", Verbose: -1, ExpectedError: `bad log format "logfmt", use one of text, json`},
+			{LogLevel: "debug", Quiet: true, Verbose: -1, ExpectedError: "--quiet can not be used with --log-level or --verbose"},
+			{LogLevel: "debug", Verbose: 3, ExpectedError: "--log-level and --verbose can not be used together"},
+		} {
+			LogLevel, LogFormat, Quiet, Verbose = testCase.LogLevel, testCase.LogFormat, testCase.Quiet, testCase.Verbose
+
+			err := SetupLogger()
+
+			require.EqualError(t, err, testCase.ExpectedError)
+			require.Equal(t, ExitValidation, ExitCode(err))
+		}
+	})
+}
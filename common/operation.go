@@ -0,0 +1,91 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/pkg/maestro"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+)
+
+// DefaultWaitTimeout is how long --wait waits for an operation on contexts
+// without defaults.waitTimeout
+const DefaultWaitTimeout = 10 * time.Minute
+
+// OperationPollInterval is the interval between operation status checks
+var OperationPollInterval = 2 * time.Second
+
+// WaitParameters tells a command to wait for the operation it enqueues
+type WaitParameters struct {
+	Wait    bool
+	Timeout time.Duration
+}
+
+// AddWaitFlags adds the --wait and --wait-timeout flags to a command that
+// enqueues an operation
+func AddWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "Waits for the operation to finish")
+	cmd.Flags().Duration("wait-timeout", DefaultWaitTimeout, "How long --wait waits for the operation, defaults to the context defaults.waitTimeout")
+}
+
+// GetWaitParameters reads the flags added by AddWaitFlags, --wait-timeout
+// falls back to the context defaults when it is not set
+func GetWaitParameters(cmd *cobra.Command, config *extensions.ContextConfig) *WaitParameters {
+	wait, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+	if !cmd.Flags().Changed("wait-timeout") && config.GetDefaults().WaitTimeout != 0 {
+		timeout = config.GetDefaults().WaitTimeout
+	}
+	return &WaitParameters{
+		Wait:    wait,
+		Timeout: timeout,
+	}
+}
+
+// WaitOperation polls the operation until it ends or the timeout expires,
+// it returns immediately when parameters do not ask to wait
+func WaitOperation(ctx context.Context, client maestro.Client, parameters *WaitParameters, schedulerName, operationID string) error {
+	if parameters == nil || !parameters.Wait {
+		return nil
+	}
+
+	logger := GetLogger()
+	logger.Sugar().Infof("waiting for operation %s", operationID)
+
+	deadline := time.Now().Add(parameters.Timeout)
+	request := &v1.GetOperationRequest{SchedulerName: schedulerName, OperationId: operationID}
+	for {
+		response, err := client.GetOperation(ctx, request)
+		if err != nil {
+			return err
+		}
+
+		operationStatus := response.GetOperation().GetStatus()
+		switch operationStatus {
+		case "finished":
+			logger.Sugar().Infof("operation %s finished", operationID)
+			return nil
+		case "error", "canceled", "evicted":
+			return fmt.Errorf("operation %s ended with status %s", operationID, operationStatus)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for operation %s, last status: %s", parameters.Timeout, operationID, operationStatus)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for operation %s: %w", operationID, ctx.Err())
+		case <-time.After(OperationPollInterval):
+		}
+	}
+}
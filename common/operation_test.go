@@ -0,0 +1,84 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/extensions"
+	"github.com/topfreegames/maestro-cli/mocks"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+)
+
+func TestWaitOperation(t *testing.T) {
+	t.Run("does not wait without parameters", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		err := WaitOperation(context.Background(), mocks.NewMockMaestroClient(mockCtrl), nil, "scheduler", "abc")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("stops waiting when the context is cancelled", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		client := mocks.NewMockMaestroClient(mockCtrl)
+		client.EXPECT().GetOperation(ctx, &v1.GetOperationRequest{SchedulerName: "scheduler", OperationId: "abc"}).
+			DoAndReturn(func(_ context.Context, _ *v1.GetOperationRequest) (*v1.GetOperationResponse, error) {
+				cancel()
+				return &v1.GetOperationResponse{Operation: &v1.Operation{Status: "in_progress"}}, nil
+			})
+
+		err := WaitOperation(ctx, client, &WaitParameters{Wait: true, Timeout: time.Hour}, "scheduler", "abc")
+
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, "stopped waiting for operation abc: context canceled", err.Error())
+	})
+}
+
+func TestGetWaitParameters(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		AddWaitFlags(cmd)
+		return cmd
+	}
+	config := &extensions.ContextConfig{Defaults: &extensions.Defaults{WaitTimeout: 5 * time.Minute}}
+
+	t.Run("waits for the context default timeout", func(t *testing.T) {
+		cmd := newCmd()
+		require.NoError(t, cmd.Flags().Set("wait", "true"))
+
+		parameters := GetWaitParameters(cmd, config)
+
+		require.Equal(t, &WaitParameters{Wait: true, Timeout: 5 * time.Minute}, parameters)
+	})
+
+	t.Run("wait timeout flag overrides the context default", func(t *testing.T) {
+		cmd := newCmd()
+		require.NoError(t, cmd.Flags().Set("wait-timeout", "30s"))
+
+		parameters := GetWaitParameters(cmd, config)
+
+		require.Equal(t, &WaitParameters{Wait: false, Timeout: 30 * time.Second}, parameters)
+	})
+
+	t.Run("waits for the default timeout without context default", func(t *testing.T) {
+		parameters := GetWaitParameters(newCmd(), &extensions.ContextConfig{})
+
+		require.Equal(t, DefaultWaitTimeout, parameters.Timeout)
+	})
+}
@@ -0,0 +1,45 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/topfreegames/maestro-cli/extensions"
+)
+
+// AssumeYes skips the confirmation of mutating commands
+var AssumeYes bool
+
+// Confirm asks the user to confirm a mutating command when the context
+// defaults require it
+func Confirm(config *extensions.ContextConfig, action string) error {
+	if AssumeYes || !config.GetDefaults().Confirm {
+		return nil
+	}
+	return confirm(os.Stdin, os.Stderr, action)
+}
+
+func confirm(in io.Reader, out io.Writer, action string) error {
+	fmt.Fprintf(out, "%s? [y/N] ", action)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("error reading confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errors.New("aborted, use --yes to skip the confirmation")
+	}
+}
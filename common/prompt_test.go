@@ -0,0 +1,51 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package common
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/extensions"
+)
+
+func TestConfirm(t *testing.T) {
+	t.Run("is skipped when the context does not require it", func(t *testing.T) {
+		err := Confirm(&extensions.ContextConfig{}, "Remove rooms")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("is skipped with --yes", func(t *testing.T) {
+		AssumeYes = true
+		defer func() { AssumeYes = false }()
+
+		err := Confirm(&extensions.ContextConfig{Defaults: &extensions.Defaults{Confirm: true}}, "Remove rooms")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("accepts yes", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		err := confirm(strings.NewReader("Yes\n"), out, "Remove rooms")
+
+		require.NoError(t, err)
+		require.Equal(t, "Remove rooms? [y/N] ", out.String())
+	})
+
+	t.Run("aborts on anything else", func(t *testing.T) {
+		for _, answer := range []string{"n\n", "\n", ""} {
+			err := confirm(strings.NewReader(answer), new(bytes.Buffer), "Remove rooms")
+
+			require.Error(t, err)
+			require.Equal(t, "aborted, use --yes to skip the confirmation", err.Error())
+		}
+	})
+}
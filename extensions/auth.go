@@ -0,0 +1,156 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// expiryDelta is how long before its expiry a token is already considered
+// expired, so it does not expire while the request is in flight
+const expiryDelta = 10 * time.Second
+
+// AuthConfig holds how requests to maestro are authenticated, only one of
+// Token, TokenFile, OAuth2 and CredentialHelper must be set
+type AuthConfig struct {
+	Token            string        `yaml:"token,omitempty"`
+	TokenFile        string        `yaml:"tokenFile,omitempty"`
+	OAuth2           *OAuth2Config `yaml:"oauth2,omitempty"`
+	CredentialHelper string        `yaml:"credentialHelper,omitempty"`
+}
+
+// redactedValue replaces secrets shown to the user
+const redactedValue = "REDACTED"
+
+func (a *AuthConfig) redacted() *AuthConfig {
+	if a == nil {
+		return nil
+	}
+	redacted := *a
+	if redacted.Token != "" {
+		redacted.Token = redactedValue
+	}
+	if a.OAuth2 != nil {
+		oauth2 := *a.OAuth2
+		if oauth2.ClientSecret != "" {
+			oauth2.ClientSecret = redactedValue
+		}
+		if oauth2.CachedToken != nil {
+			oauth2.CachedToken = &Token{
+				AccessToken: redactedValue,
+				TokenType:   oauth2.CachedToken.TokenType,
+				Expiry:      oauth2.CachedToken.Expiry,
+			}
+			if a.OAuth2.CachedToken.RefreshToken != "" {
+				oauth2.CachedToken.RefreshToken = redactedValue
+			}
+		}
+		redacted.OAuth2 = &oauth2
+	}
+	return &redacted
+}
+
+// Token is an access token sent on the Authorization header
+type Token struct {
+	AccessToken  string    `yaml:"accessToken"`
+	TokenType    string    `yaml:"tokenType,omitempty"`
+	RefreshToken string    `yaml:"refreshToken,omitempty"`
+	Expiry       time.Time `yaml:"expiry,omitempty"`
+}
+
+// Valid returns true if the token can still be used
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry)
+}
+
+// AuthorizationHeader returns the Authorization header value for the token
+func (t *Token) AuthorizationHeader() string {
+	tokenType := t.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	return tokenType + " " + t.AccessToken
+}
+
+// TokenSource provides the token used to authenticate requests, ctx cancels
+// the requests or commands obtaining it
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// NewTokenSource returns the TokenSource for the auth settings of the named
+// context, or nil if requests are not authenticated. onRefresh is called every
+// time a new OAuth2 token is obtained so it can be cached.
+func NewTokenSource(contextName string, config *ContextConfig, onRefresh func(*Token)) (TokenSource, error) {
+	auth := config.Auth
+	if auth == nil {
+		return nil, nil
+	}
+	if auth.methods() > 1 {
+		return nil, errors.New("auth must have only one of token, tokenFile, oauth2 or credentialHelper")
+	}
+
+	switch {
+	case auth.Token != "":
+		return &staticTokenSource{token: &Token{AccessToken: auth.Token}}, nil
+	case auth.TokenFile != "":
+		return &fileTokenSource{path: auth.TokenFile}, nil
+	case auth.OAuth2 != nil:
+		return NewOAuth2TokenSource(auth.OAuth2, onRefresh), nil
+	case auth.CredentialHelper != "":
+		request := CredentialRequest{Context: contextName, ServerURL: config.ServerURL}
+		return NewCredentialHelperTokenSource(auth.CredentialHelper, request), nil
+	default:
+		return nil, nil
+	}
+}
+
+// methods returns how many auth methods are set
+func (a *AuthConfig) methods() int {
+	set := 0
+	for _, isSet := range []bool{a.Token != "", a.TokenFile != "", a.OAuth2 != nil, a.CredentialHelper != ""} {
+		if isSet {
+			set++
+		}
+	}
+	return set
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s *staticTokenSource) Token(context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+// fileTokenSource reads the token on every call, so the file can be rotated
+// by other tools while maestro-cli runs
+type fileTokenSource struct {
+	path string
+}
+
+func (s *fileTokenSource) Token(context.Context) (*Token, error) {
+	bts, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading token file: %w", err)
+	}
+	accessToken := strings.TrimSpace(string(bts))
+	if accessToken == "" {
+		return nil, fmt.Errorf("token file %s is empty", s.path)
+	}
+	return &Token{AccessToken: accessToken}, nil
+}
@@ -0,0 +1,191 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAuthorization(t *testing.T) {
+	var authorization string
+	maestro := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer maestro.Close()
+
+	t.Run("sends static token", func(t *testing.T) {
+		tokenSource, err := NewTokenSource("test", &ContextConfig{Auth: &AuthConfig{Token: "static-token"}}, nil)
+		require.NoError(t, err)
+
+		client, err := NewClient(&ContextConfig{}, tokenSource)
+		require.NoError(t, err)
+		_, status, err := client.Get(context.Background(), maestro.URL, "")
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "Bearer static-token", authorization)
+	})
+
+	t.Run("sends token read from file on every request", func(t *testing.T) {
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, ioutil.WriteFile(tokenFile, []byte("first-token\n"), 0600))
+
+		tokenSource, err := NewTokenSource("test", &ContextConfig{Auth: &AuthConfig{TokenFile: tokenFile}}, nil)
+		require.NoError(t, err)
+		client, err := NewClient(&ContextConfig{}, tokenSource)
+		require.NoError(t, err)
+
+		_, _, err = client.Delete(context.Background(), maestro.URL)
+		require.NoError(t, err)
+		require.Equal(t, "Bearer first-token", authorization)
+
+		require.NoError(t, ioutil.WriteFile(tokenFile, []byte("second-token"), 0600))
+		_, _, err = client.Post(context.Background(), maestro.URL, "{}")
+		require.NoError(t, err)
+		require.Equal(t, "Bearer second-token", authorization)
+	})
+
+	t.Run("sends no header when auth is not configured", func(t *testing.T) {
+		tokenSource, err := NewTokenSource("test", &ContextConfig{}, nil)
+		require.NoError(t, err)
+
+		client, err := NewClient(&ContextConfig{}, tokenSource)
+		require.NoError(t, err)
+		_, _, err = client.Get(context.Background(), maestro.URL, "")
+
+		require.NoError(t, err)
+		require.Empty(t, authorization)
+	})
+
+	t.Run("fails when more than one auth method is configured", func(t *testing.T) {
+		_, err := NewTokenSource("test", &ContextConfig{Auth: &AuthConfig{Token: "token", TokenFile: "/tmp/token"}}, nil)
+
+		require.Error(t, err)
+		require.Equal(t, "auth must have only one of token, tokenFile, oauth2 or credentialHelper", err.Error())
+	})
+}
+
+func TestOAuth2TokenSource(t *testing.T) {
+	var requests []string
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		grantType := r.Form.Get("grant_type")
+		requests = append(requests, grantType)
+
+		switch {
+		case r.URL.Path == "/device":
+			fmt.Fprint(w, `{"device_code":"dev-code","user_code":"ABCD","verification_uri":"https://auth.example.com/device","expires_in":60}`)
+		case grantType == "client_credentials":
+			fmt.Fprint(w, `{"access_token":"cc-token","token_type":"bearer","expires_in":3600}`)
+		case grantType == "refresh_token":
+			fmt.Fprintf(w, `{"access_token":"refreshed-%s","expires_in":3600}`, r.Form.Get("refresh_token"))
+		case grantType == deviceCodeGrantType && len(requests) < 3:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"authorization_pending"}`)
+		case grantType == deviceCodeGrantType:
+			fmt.Fprint(w, `{"access_token":"device-token","refresh_token":"device-refresh","expires_in":3600}`)
+		}
+	}))
+	defer tokenServer.Close()
+
+	t.Run("client credentials token is fetched once and cached", func(t *testing.T) {
+		requests = nil
+		var refreshed []*Token
+		config := &OAuth2Config{Flow: OAuth2ClientCredentials, TokenURL: tokenServer.URL, ClientID: "id", ClientSecret: "secret"}
+		tokenSource := NewOAuth2TokenSource(config, func(token *Token) { refreshed = append(refreshed, token) })
+
+		token, err := tokenSource.Token(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "cc-token", token.AccessToken)
+
+		token, err = tokenSource.Token(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "cc-token", token.AccessToken)
+		require.Equal(t, []string{"client_credentials"}, requests)
+		require.Len(t, refreshed, 1)
+	})
+
+	t.Run("expired token is refreshed", func(t *testing.T) {
+		requests = nil
+		config := &OAuth2Config{
+			Flow:     OAuth2DeviceCode,
+			TokenURL: tokenServer.URL,
+			ClientID: "id",
+			CachedToken: &Token{
+				AccessToken:  "old-token",
+				RefreshToken: "refresh",
+				Expiry:       time.Now().Add(-time.Minute),
+			},
+		}
+
+		token, err := NewOAuth2TokenSource(config, nil).Token(context.Background())
+
+		require.NoError(t, err)
+		require.Equal(t, "refreshed-refresh", token.AccessToken)
+		require.Equal(t, "refresh", token.RefreshToken)
+		require.Equal(t, []string{"refresh_token"}, requests)
+	})
+
+	t.Run("device code flow without token requires login", func(t *testing.T) {
+		config := &OAuth2Config{Flow: OAuth2DeviceCode, TokenURL: tokenServer.URL, ClientID: "id"}
+
+		_, err := NewOAuth2TokenSource(config, nil).Token(context.Background())
+
+		require.ErrorIs(t, err, ErrLoginRequired)
+	})
+
+	t.Run("device code login polls until authorized", func(t *testing.T) {
+		requests = nil
+		config := &OAuth2Config{
+			Flow:          OAuth2DeviceCode,
+			TokenURL:      tokenServer.URL,
+			DeviceAuthURL: tokenServer.URL + "/device",
+			ClientID:      "id",
+		}
+		tokenSource := NewOAuth2TokenSource(config, nil)
+		tokenSource.pollInterval = time.Millisecond
+
+		out := new(bytes.Buffer)
+		token, err := tokenSource.Login(context.Background(), out)
+
+		require.NoError(t, err)
+		require.Equal(t, "device-token", token.AccessToken)
+		require.Equal(t, token, config.CachedToken)
+		require.Equal(t, "Open https://auth.example.com/device and enter the code ABCD to authorize maestro-cli\n", out.String())
+	})
+
+	t.Run("device code login stops polling when cancelled", func(t *testing.T) {
+		requests = nil
+		config := &OAuth2Config{
+			Flow:          OAuth2DeviceCode,
+			TokenURL:      tokenServer.URL,
+			DeviceAuthURL: tokenServer.URL + "/device",
+			ClientID:      "id",
+		}
+		tokenSource := NewOAuth2TokenSource(config, nil)
+		tokenSource.pollInterval = time.Hour
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := tokenSource.Login(ctx, new(bytes.Buffer))
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, []string{""}, requests)
+		require.Nil(t, config.CachedToken)
+	})
+}
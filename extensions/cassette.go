@@ -0,0 +1,234 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/topfreegames/maestro-cli/interfaces"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Interaction is a request and its response, stored as a YAML document of a
+// cassette file
+type Interaction struct {
+	Method   string `yaml:"method"`
+	URL      string `yaml:"url"`
+	Body     string `yaml:"body,omitempty"`
+	Status   int    `yaml:"status,omitempty"`
+	Response string `yaml:"response,omitempty"`
+	// Error is the message of the request error, e.g. a connection refused
+	Error string `yaml:"error,omitempty"`
+}
+
+// matches compares method, URL path and query, and the body with JSON
+// normalized and redacted like the recorded one, so cassettes replay on any
+// server URL
+func (i *Interaction) matches(method, rawURL, body string, redactFields []string) bool {
+	return i.Method == method &&
+		normalizeURL(i.URL) == normalizeURL(rawURL) &&
+		normalizeBody(i.Body) == normalizeBody(string(RedactBody([]byte(body), redactFields)))
+}
+
+func normalizeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.RawQuery = u.Query().Encode()
+	return u.RequestURI()
+}
+
+func normalizeBody(body string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return strings.TrimSpace(body)
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return strings.TrimSpace(body)
+	}
+	return string(normalized)
+}
+
+// Recorder is a client appending every request it sends and the response to
+// a cassette file, with the secrets of the bodies redacted like the traces
+type Recorder struct {
+	client       interfaces.Client
+	path         string
+	redactFields []string
+	mu           sync.Mutex
+}
+
+// NewRecorder ctor, requests are sent by client and the body fields matching
+// redactFields are redacted in the cassette
+func NewRecorder(client interfaces.Client, path string, redactFields []string) *Recorder {
+	return &Recorder{
+		client:       client,
+		path:         path,
+		redactFields: redactFields,
+	}
+}
+
+// Get does a get request
+func (r *Recorder) Get(ctx context.Context, url, body string) ([]byte, int, error) {
+	response, status, err := r.client.Get(ctx, url, body)
+	return r.record("GET", url, body, response, status, err)
+}
+
+// Put does a put request
+func (r *Recorder) Put(ctx context.Context, url, body string) ([]byte, int, error) {
+	response, status, err := r.client.Put(ctx, url, body)
+	return r.record("PUT", url, body, response, status, err)
+}
+
+// Post does a post request
+func (r *Recorder) Post(ctx context.Context, url, body string) ([]byte, int, error) {
+	response, status, err := r.client.Post(ctx, url, body)
+	return r.record("POST", url, body, response, status, err)
+}
+
+// IdempotentPost does a post request with an idempotency key, the key is not
+// recorded
+func (r *Recorder) IdempotentPost(ctx context.Context, url, body, idempotencyKey string) ([]byte, int, error) {
+	response, status, err := r.client.IdempotentPost(ctx, url, body, idempotencyKey)
+	return r.record("POST", url, body, response, status, err)
+}
+
+// Delete does a delete request
+func (r *Recorder) Delete(ctx context.Context, url string) ([]byte, int, error) {
+	response, status, err := r.client.Delete(ctx, url)
+	return r.record("DELETE", url, "", response, status, err)
+}
+
+func (r *Recorder) record(method, url, body string, response []byte, status int, err error) ([]byte, int, error) {
+	interaction := &Interaction{
+		Method:   method,
+		URL:      url,
+		Body:     string(RedactBody([]byte(body), r.redactFields)),
+		Status:   status,
+		Response: string(RedactBody(response, r.redactFields)),
+	}
+	if err != nil {
+		interaction.Error = err.Error()
+	}
+
+	document, marshalErr := yaml.Marshal(interaction)
+	if marshalErr != nil {
+		return response, status, fmt.Errorf("error recording %s %s: %w", method, url, marshalErr)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	file, openErr := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if openErr != nil {
+		return response, status, fmt.Errorf("error recording %s %s: %w", method, url, openErr)
+	}
+	defer file.Close()
+	_, writeErr := file.Write(append([]byte("---\n"), document...))
+	if writeErr != nil {
+		return response, status, fmt.Errorf("error recording %s %s: %w", method, url, writeErr)
+	}
+	return response, status, err
+}
+
+// Replayer is a client responding with the interactions of a cassette file,
+// no request is sent. Interactions are replayed once and in order, so a
+// cassette can hold the successive responses of the same request.
+type Replayer struct {
+	path         string
+	interactions []*Interaction
+	replayed     []bool
+	redactFields []string
+	mu           sync.Mutex
+}
+
+// NewReplayer ctor, it reads the cassette at path. Request bodies are
+// redacted with redactFields before matching, as they were when recorded.
+func NewReplayer(path string, redactFields []string) (*Replayer, error) {
+	bts, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(bts))
+	var interactions []*Interaction
+	for {
+		interaction := &Interaction{}
+		err := decoder.Decode(interaction)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing cassette %s: %w", path, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+
+	return &Replayer{
+		path:         path,
+		interactions: interactions,
+		replayed:     make([]bool, len(interactions)),
+		redactFields: redactFields,
+	}, nil
+}
+
+// Get does a get request
+func (r *Replayer) Get(ctx context.Context, url, body string) ([]byte, int, error) {
+	return r.replay(ctx, "GET", url, body)
+}
+
+// Put does a put request
+func (r *Replayer) Put(ctx context.Context, url, body string) ([]byte, int, error) {
+	return r.replay(ctx, "PUT", url, body)
+}
+
+// Post does a post request
+func (r *Replayer) Post(ctx context.Context, url, body string) ([]byte, int, error) {
+	return r.replay(ctx, "POST", url, body)
+}
+
+// IdempotentPost does a post request, the idempotency key is not matched
+func (r *Replayer) IdempotentPost(ctx context.Context, url, body, _ string) ([]byte, int, error) {
+	return r.replay(ctx, "POST", url, body)
+}
+
+// Delete does a delete request
+func (r *Replayer) Delete(ctx context.Context, url string) ([]byte, int, error) {
+	return r.replay(ctx, "DELETE", url, "")
+}
+
+func (r *Replayer) replay(ctx context.Context, method, url, body string) ([]byte, int, error) {
+	if ctx.Err() != nil {
+		return nil, 0, ctx.Err()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !interaction.matches(method, url, body, r.redactFields) {
+			continue
+		}
+		r.replayed[i] = true
+		if interaction.Error != "" {
+			return nil, 0, errors.New(interaction.Error)
+		}
+		return []byte(interaction.Response), interaction.Status, nil
+	}
+	return nil, 0, fmt.Errorf("no interaction of cassette %s matches %s %s", r.path, method, url)
+}
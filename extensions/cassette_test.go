@@ -0,0 +1,119 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+func TestCassette(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replays the recorded interactions", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		gomock.InOrder(
+			client.EXPECT().IdempotentPost(ctx, "http://localhost:8080/schedulers/scheduler/add-rooms", `{"amount": 10}`, "key").
+				Return([]byte(`{"operationId":"abc"}`), 200, nil),
+			client.EXPECT().Get(ctx, "http://localhost:8080/schedulers/scheduler/operations/abc", "").
+				Return([]byte(`{"operation":{"status":"in_progress"}}`), 200, nil),
+			client.EXPECT().Get(ctx, "http://localhost:8080/schedulers/scheduler/operations/abc", "").
+				Return([]byte(`{"operation":{"status":"finished"}}`), 200, nil),
+			client.EXPECT().Delete(ctx, "http://localhost:8080/schedulers/other").
+				Return(nil, 0, errors.New("connection refused")),
+		)
+		path := filepath.Join(t.TempDir(), "cassette.yaml")
+		recorder := NewRecorder(client, path, DefaultRedactFields)
+
+		_, _, err := recorder.IdempotentPost(ctx, "http://localhost:8080/schedulers/scheduler/add-rooms", `{"amount": 10}`, "key")
+		require.NoError(t, err)
+		_, _, err = recorder.Get(ctx, "http://localhost:8080/schedulers/scheduler/operations/abc", "")
+		require.NoError(t, err)
+		_, _, err = recorder.Get(ctx, "http://localhost:8080/schedulers/scheduler/operations/abc", "")
+		require.NoError(t, err)
+		_, _, err = recorder.Delete(ctx, "http://localhost:8080/schedulers/other")
+		require.EqualError(t, err, "connection refused")
+
+		replayer, err := NewReplayer(path, DefaultRedactFields)
+		require.NoError(t, err)
+
+		// bodies are matched with JSON normalized and the server URL ignored
+		body, status, err := replayer.IdempotentPost(ctx, "https://other.server/schedulers/scheduler/add-rooms", `{"amount":10}`, "other-key")
+		require.NoError(t, err)
+		require.Equal(t, 200, status)
+		require.Equal(t, `{"operationId":"abc"}`, string(body))
+
+		body, _, err = replayer.Get(ctx, "https://other.server/schedulers/scheduler/operations/abc", "")
+		require.NoError(t, err)
+		require.Equal(t, `{"operation":{"status":"in_progress"}}`, string(body))
+		body, _, err = replayer.Get(ctx, "https://other.server/schedulers/scheduler/operations/abc", "")
+		require.NoError(t, err)
+		require.Equal(t, `{"operation":{"status":"finished"}}`, string(body))
+
+		_, _, err = replayer.Delete(ctx, "https://other.server/schedulers/other")
+		require.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("redacts the secrets of the bodies", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		request := `{"name":"scheduler","spec":{"containers":[{"environment":[{"name":"API_KEY","value":"env-secret"}]}]}}`
+		client.EXPECT().Post(ctx, "http://localhost:8080/schedulers", request).
+			Return([]byte(`{"scheduler":{"name":"scheduler"},"accessToken":"response-secret","roomId":9007199254740993}`), 200, nil)
+		path := filepath.Join(t.TempDir(), "cassette.yaml")
+		recorder := NewRecorder(client, path, append(DefaultRedactFields, "api_key"))
+
+		body, _, err := recorder.Post(ctx, "http://localhost:8080/schedulers", request)
+		require.NoError(t, err)
+		require.Contains(t, string(body), "response-secret")
+
+		bts, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.NotContains(t, string(bts), "env-secret")
+		require.NotContains(t, string(bts), "response-secret")
+		require.Contains(t, string(bts), Redacted)
+		require.Contains(t, string(bts), "9007199254740993")
+
+		replayer, err := NewReplayer(path, append(DefaultRedactFields, "api_key"))
+		require.NoError(t, err)
+		body, _, err = replayer.Post(ctx, "http://localhost:8080/schedulers", request)
+		require.NoError(t, err)
+		require.Contains(t, string(body), `"accessToken":"REDACTED"`)
+	})
+
+	t.Run("fails on requests without interaction", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cassette.yaml")
+		cassette := "---\nmethod: POST\nurl: http://localhost:8080/schedulers/scheduler/remove-rooms\nbody: '{\"amount\":10}'\nstatus: 200\nresponse: '{}'\n"
+		require.NoError(t, ioutil.WriteFile(path, []byte(cassette), 0600))
+		replayer, err := NewReplayer(path, DefaultRedactFields)
+		require.NoError(t, err)
+
+		_, _, err = replayer.Post(ctx, "http://localhost:8080/schedulers/scheduler/remove-rooms", `{"amount":5}`)
+		require.EqualError(t, err, "no interaction of cassette "+path+" matches POST http://localhost:8080/schedulers/scheduler/remove-rooms")
+
+		_, _, err = replayer.Post(ctx, "http://localhost:8080/schedulers/scheduler/remove-rooms", `{"amount":10}`)
+		require.NoError(t, err)
+		_, _, err = replayer.Post(ctx, "http://localhost:8080/schedulers/scheduler/remove-rooms", `{"amount":10}`)
+		require.Error(t, err)
+	})
+
+	t.Run("fails when the cassette does not exist", func(t *testing.T) {
+		_, err := NewReplayer(filepath.Join(t.TempDir(), "missing.yaml"), DefaultRedactFields)
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error reading cassette")
+	})
+}
@@ -8,6 +8,9 @@
 package extensions
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -16,69 +19,171 @@ import (
 
 // Client struct
 type Client struct {
-	client *http.Client
+	client      *http.Client
+	tokenSource TokenSource
+	timeout     time.Duration
+	retry       *RetryConfig
+	trace       *TraceConfig
+	sleep       func(context.Context, time.Duration) error
 }
 
-// NewClient ctor
-func NewClient(config *Config) *Client {
+// NewClient ctor, tokenSource may be nil when requests are not authenticated
+func NewClient(config *ContextConfig, tokenSource TokenSource) (*Client, error) {
+	tlsConfig, err := NewTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
 	h := &Client{}
 	h.client = &http.Client{
-		Timeout: 20 * time.Minute,
+		Transport: newTransport(config, tlsConfig),
+	}
+	h.tokenSource = tokenSource
+	h.timeout = timeout
+	h.retry = config.Retry
+	h.trace = config.Trace
+	h.sleep = sleep
+	return h, nil
+}
+
+// EnableTrace writes every request and response to out, with the
+// Authorization header and the secret fields of the bodies redacted. With curl
+// every request is followed by the curl command sending it.
+func (c *Client) EnableTrace(out io.Writer, curl bool) {
+	c.client.Transport = &traceTransport{
+		next:         c.client.Transport,
+		out:          out,
+		curl:         curl,
+		redactFields: c.trace.GetRedactFields(),
 	}
-	return h
 }
 
 // Get does a get request
-func (c *Client) Get(url, body string) ([]byte, int, error) {
-	return c.requestWithBody("GET", url, body)
+func (c *Client) Get(ctx context.Context, url, body string) ([]byte, int, error) {
+	return c.requestWithBody(ctx, "GET", url, body, nil, true)
 }
 
 // Put does a put request
-func (c *Client) Put(url, body string) ([]byte, int, error) {
-	return c.requestWithBody("PUT", url, body)
+func (c *Client) Put(ctx context.Context, url, body string) ([]byte, int, error) {
+	return c.requestWithBody(ctx, "PUT", url, body, nil, true)
 }
 
-// Post does a post request
-func (c *Client) Post(url, body string) ([]byte, int, error) {
-	return c.requestWithBody("POST", url, body)
+// Post does a post request, it is not retried
+func (c *Client) Post(ctx context.Context, url, body string) ([]byte, int, error) {
+	return c.requestWithBody(ctx, "POST", url, body, nil, false)
 }
 
-// Delete does a put request
-func (c *Client) Delete(url string) ([]byte, int, error) {
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return nil, 0, err
+// IdempotentPost does a post request sending the Idempotency-Key header, so
+// it is retried like the idempotent methods
+func (c *Client) IdempotentPost(ctx context.Context, url, body, idempotencyKey string) ([]byte, int, error) {
+	header := http.Header{"Idempotency-Key": {idempotencyKey}}
+	return c.requestWithBody(ctx, "POST", url, body, header, true)
+}
+
+// Delete does a delete request
+func (c *Client) Delete(ctx context.Context, url string) ([]byte, int, error) {
+	return c.requestWithBody(ctx, "DELETE", url, "", nil, true)
+}
+
+func (c *Client) requestWithBody(ctx context.Context, method, url, body string, header http.Header, retry bool) ([]byte, int, error) {
+	return c.retryRequest(ctx, func(ctx context.Context) (*http.Request, error) {
+		var ioBody io.Reader
+		if body != "" {
+			ioBody = strings.NewReader(body)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, ioBody)
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		return req, nil
+	}, retry)
+}
+
+// retryRequest sends the request built by newRequest, retrying transient
+// failures with exponential backoff when retry is true. Every attempt has its
+// own deadline, that also bounds getting its auth token, and ctx cancels them
+// all.
+func (c *Client) retryRequest(ctx context.Context, newRequest func(context.Context) (*http.Request, error), retry bool) ([]byte, int, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		req, err := newRequest(attemptCtx)
+		if err == nil {
+			err = c.authorize(req)
+		}
+		if err != nil {
+			cancel()
+			return nil, 0, err
+		}
+
+		responseBody, status, wait, err := c.send(req)
+		cancel()
+		if ctx.Err() != nil || !retry || !shouldRetry(status, err) || attempt >= c.retry.GetMaxAttempts() {
+			return responseBody, status, err
+		}
+		wait = backoff(attempt, wait)
+		if time.Since(start)+wait > c.retry.GetMaxElapsedTime() {
+			return responseBody, status, err
+		}
+		err = c.sleep(ctx, wait)
+		if err != nil {
+			return nil, 0, err
+		}
 	}
+}
 
+// send does a single attempt, returning the Retry-After of the response
+func (c *Client) send(req *http.Request) ([]byte, int, time.Duration, error) {
 	res, err := c.client.Do(req)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
 	defer res.Body.Close()
 	responseBody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
-	return responseBody, res.StatusCode, nil
+	return responseBody, res.StatusCode, retryAfter(res), nil
 }
 
-func (c *Client) requestWithBody(method, url, body string) ([]byte, int, error) {
-	ioBody := strings.NewReader(body)
-
-	req, err := http.NewRequest(method, url, ioBody)
+// Do sends an authorized request, the caller must close the response body
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	err := c.authorize(req)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-	req.Close = true
+	return c.client.Do(req)
+}
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, 0, err
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
-	defer res.Body.Close()
-	responseBody, err := ioutil.ReadAll(res.Body)
+}
+
+// authorize sets the Authorization header, refreshing the token if needed
+// with the context of req
+func (c *Client) authorize(req *http.Request) error {
+	if c.tokenSource == nil {
+		return nil
+	}
+	token, err := c.tokenSource.Token(req.Context())
 	if err != nil {
-		return nil, 0, err
+		return fmt.Errorf("error getting auth token: %w", err)
 	}
-	return responseBody, res.StatusCode, nil
+	req.Header.Set("Authorization", token.AuthorizationHeader())
+	return nil
 }
@@ -9,23 +9,84 @@ package extensions
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
+	"sort"
+	"time"
 
+	"github.com/topfreegames/maestro-cli/formats"
 	"github.com/topfreegames/maestro-cli/interfaces"
 	yaml "gopkg.in/yaml.v2"
 )
 
-// Config struct
+// DefaultTimeout is the request timeout of contexts without one
+const DefaultTimeout = 20 * time.Minute
+
+// Config is the maestro-cli configuration document. Like a kubeconfig, it
+// holds the settings of every context and a pointer to the current one.
 type Config struct {
-	ServerURL string `yaml:"serverUrl"`
+	Version        int                       `yaml:"version"`
+	CurrentContext string                    `yaml:"currentContext,omitempty"`
+	Contexts       map[string]*ContextConfig `yaml:"contexts"`
+
+	// Migrations describes the migrations applied while reading the file
+	Migrations []string `yaml:"-"`
+}
+
+// ContextConfig holds the settings of a single maestro context
+type ContextConfig struct {
+	ServerURL          string        `yaml:"serverUrl"`
+	Auth               *AuthConfig   `yaml:"auth,omitempty"`
+	CAFile             string        `yaml:"caFile,omitempty"`
+	CertFile           string        `yaml:"certFile,omitempty"`
+	KeyFile            string        `yaml:"keyFile,omitempty"`
+	ServerName         string        `yaml:"serverName,omitempty"`
+	InsecureSkipVerify bool          `yaml:"insecureSkipVerify,omitempty"`
+	Timeout            time.Duration `yaml:"timeout,omitempty"`
+	Retry              *RetryConfig  `yaml:"retry,omitempty"`
+	Pool               *PoolConfig   `yaml:"pool,omitempty"`
+	Defaults           *Defaults     `yaml:"defaults,omitempty"`
+	Trace              *TraceConfig  `yaml:"trace,omitempty"`
+	// Transport is http, calling the HTTP gateway, or grpc
+	Transport string `yaml:"transport,omitempty"`
+	// Proxy is the http, https or socks5 proxy URL of the requests, hosts
+	// listed by NO_PROXY are reached directly
+	Proxy string `yaml:"proxy,omitempty"`
+}
+
+// Defaults are applied by every command unless the user sets the matching
+// flag or argument
+type Defaults struct {
+	Game        string        `yaml:"game,omitempty"`
+	Output      string        `yaml:"output,omitempty"`
+	WaitTimeout time.Duration `yaml:"waitTimeout,omitempty"`
+	Confirm     bool          `yaml:"confirm,omitempty"`
 }
 
+// OutputFormats are the formats accepted by defaults.output
+var OutputFormats = formats.Formats
+
 // NewConfig ctor
-func NewConfig(serverURL string) *Config {
+func NewConfig() *Config {
 	c := &Config{
+		Version:  CurrentVersion,
+		Contexts: map[string]*ContextConfig{},
+	}
+	return c
+}
+
+// GetDefaults returns the context defaults, never nil
+func (c *ContextConfig) GetDefaults() *Defaults {
+	if c.Defaults == nil {
+		return &Defaults{}
+	}
+	return c.Defaults
+}
+
+// NewContextConfig ctor
+func NewContextConfig(serverURL string) *ContextConfig {
+	c := &ContextConfig{
 		ServerURL: serverURL,
 	}
 	return c
@@ -49,60 +110,213 @@ func getDirPath() (string, error) {
 	return dirPath, nil
 }
 
-func getConfigPath(context string) (string, error) {
+func getConfigPath() (string, error) {
 	dir, err := getDirPath()
 	if err != nil {
 		return "", err
 	}
-	fileName := fmt.Sprintf("config-%s.yaml", context)
-	configPath := filepath.Join(dir, fileName)
+	configPath := filepath.Join(dir, "config.yaml")
 	return configPath, nil
 }
 
-// ReadConfig from file
-func ReadConfig(fs interfaces.FileSystem, context string) (*Config, error) {
-	configPath, err := getConfigPath(context)
+// ReadConfig from file. Config files written by previous maestro-cli versions
+// are migrated in place, keeping a backup. The settings are not validated, see
+// Check and CheckContext.
+func ReadConfig(fs interfaces.FileSystem) (*Config, error) {
+	configPath, err := getConfigPath()
 	if err != nil {
 		return nil, err
 	}
-	if _, err := fs.Stat(configPath); fs.IsNotExist(err) {
+	bts, err := fs.ReadFile(configPath)
+	if fs.IsNotExist(err) {
+		return migrateLegacyConfig(fs)
+	}
+	if err != nil {
 		return nil, err
 	}
-	bts, err := ioutil.ReadFile(configPath)
+
+	doc := map[string]interface{}{}
+	err = yaml.Unmarshal(bts, &doc)
+	if err != nil {
+		return nil, &ConfigError{Path: configPath, Err: err, Hint: "fix the YAML syntax of the file"}
+	}
+
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, &ConfigError{Path: configPath, Err: err, Hint: fmt.Sprintf("set version to %d", CurrentVersion)}
+	}
+	if version > CurrentVersion {
+		return nil, &ConfigError{
+			Path: configPath,
+			Err:  fmt.Errorf("version %d is newer than the supported version %d", version, CurrentVersion),
+			Hint: "upgrade maestro-cli",
+		}
+	}
+	if version == CurrentVersion {
+		return decodeConfig(configPath, doc)
+	}
+
+	err = migrate(doc, version)
+	if err != nil {
+		return nil, &ConfigError{Path: configPath, Err: err, Hint: "fix the file or remove it and run maestro-cli init again"}
+	}
+	c, err := decodeConfig(configPath, doc)
 	if err != nil {
 		return nil, err
 	}
-	c := &Config{}
-	err = yaml.Unmarshal(bts, c)
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", configPath, version)
+	err = writeFile(fs, backupPath, bts)
+	if err != nil {
+		return nil, fmt.Errorf("error backing up config file: %w", err)
+	}
+	err = c.Write(fs)
+	if err != nil {
+		return nil, fmt.Errorf("error writing migrated config file: %w", err)
+	}
+	c.Migrations = append(c.Migrations, fmt.Sprintf("migrated %s from version %d to %d, backup at %s", configPath, version, CurrentVersion, backupPath))
+	return c, nil
+}
+
+// decodeConfig strictly decodes a migrated document, rejecting unknown
+// fields
+func decodeConfig(configPath string, doc map[string]interface{}) (*Config, error) {
+	bts, err := yaml.Marshal(doc)
 	if err != nil {
 		return nil, err
 	}
+	c := NewConfig()
+	err = yaml.UnmarshalStrict(bts, c)
+	if err != nil {
+		return nil, &ConfigError{Path: configPath, Err: err, Hint: "remove unknown fields or upgrade maestro-cli"}
+	}
+	if c.Contexts == nil {
+		c.Contexts = map[string]*ContextConfig{}
+	}
 	return c, nil
 }
 
-// Write the config file to disk
-func (c *Config) Write(fs interfaces.FileSystem, context string) error {
-	configPath, err := getConfigPath(context)
+// Context returns the settings of the named context
+func (c *Config) Context(name string) (*ContextConfig, error) {
+	contextConfig, ok := c.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("context %q not found", name)
+	}
+	return contextConfig, nil
+}
+
+// ContextNames returns the name of every context, sorted
+func (c *Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetContext adds or replaces the named context. The first context added
+// becomes the current one.
+func (c *Config) SetContext(name string, contextConfig *ContextConfig) {
+	c.Contexts[name] = contextConfig
+	if c.CurrentContext == "" {
+		c.CurrentContext = name
+	}
+}
+
+// Use sets the current context
+func (c *Config) Use(name string) error {
+	if _, err := c.Context(name); err != nil {
+		return err
+	}
+	c.CurrentContext = name
+	return nil
+}
+
+// Rename renames a context, keeping it current if it was
+func (c *Config) Rename(oldName, newName string) error {
+	contextConfig, err := c.Context(oldName)
 	if err != nil {
 		return err
 	}
-	cfg, err := yaml.Marshal(c)
+	if _, ok := c.Contexts[newName]; ok {
+		return fmt.Errorf("context %q already exists", newName)
+	}
+	delete(c.Contexts, oldName)
+	c.Contexts[newName] = contextConfig
+	if c.CurrentContext == oldName {
+		c.CurrentContext = newName
+	}
+	return nil
+}
+
+// Delete removes a context, unsetting the current context if it was the
+// deleted one
+func (c *Config) Delete(name string) error {
+	if _, err := c.Context(name); err != nil {
+		return err
+	}
+	delete(c.Contexts, name)
+	if c.CurrentContext == name {
+		c.CurrentContext = ""
+	}
+	return nil
+}
+
+// Redacted returns a copy of the config with every secret replaced, so it can
+// be shown to the user
+func (c *Config) Redacted() *Config {
+	redacted := NewConfig()
+	redacted.Version = c.Version
+	redacted.CurrentContext = c.CurrentContext
+	for name, contextConfig := range c.Contexts {
+		copied := *contextConfig
+		copied.Auth = contextConfig.Auth.redacted()
+		redacted.Contexts[name] = &copied
+	}
+	return redacted
+}
+
+// Write the config file to disk
+func (c *Config) Write(fs interfaces.FileSystem) error {
+	configPath, err := getConfigPath()
 	if err != nil {
 		return err
 	}
-	cfgDir, err := getDirPath()
+	c.Version = CurrentVersion
+	cfg, err := yaml.Marshal(c)
 	if err != nil {
 		return err
 	}
-	err = fs.MkdirAll(cfgDir, os.ModePerm)
+	return writeFile(fs, configPath, cfg)
+}
+
+// Config files are written readable only by their owner, as they may hold
+// tokens and client secrets
+const (
+	ConfigDirPerm  os.FileMode = 0700
+	ConfigFileFlag             = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	ConfigFilePerm os.FileMode = 0600
+)
+
+func writeFile(fs interfaces.FileSystem, path string, content []byte) error {
+	err := fs.MkdirAll(filepath.Dir(path), ConfigDirPerm)
 	if err != nil {
 		return err
 	}
-	file, err := fs.Create(configPath)
+	file, err := fs.OpenFile(path, ConfigFileFlag, ConfigFilePerm)
 	if err != nil {
 		return err
 	}
-	_, err = file.Write(cfg)
+	defer file.Close()
+	// files written by previous versions were readable by every user
+	if chmoder, ok := file.(interface{ Chmod(os.FileMode) error }); ok {
+		err = chmoder.Chmod(ConfigFilePerm)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = file.Write(content)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,250 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/mocks"
+)
+
+func TestReadConfig(t *testing.T) {
+	readConfig := func(t *testing.T, content string) (*Config, error) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(content), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+
+		return ReadConfig(fs)
+	}
+
+	t.Run("reads current version", func(t *testing.T) {
+		config, err := readConfig(t, "version: 1\ncurrentContext: prod\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    timeout: 30s\n")
+
+		require.NoError(t, err)
+		require.Equal(t, "prod", config.CurrentContext)
+		require.Equal(t, "https://maestro.example.com", config.Contexts["prod"].ServerURL)
+		require.Empty(t, config.Migrations)
+	})
+
+	t.Run("reads context defaults", func(t *testing.T) {
+		config, err := readConfig(t, "version: 1\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    defaults:\n      game: the-game\n      output: table\n      waitTimeout: 5m\n      confirm: true\n")
+
+		require.NoError(t, err)
+		require.Equal(t, &Defaults{Game: "the-game", Output: "table", WaitTimeout: 5 * time.Minute, Confirm: true}, config.Contexts["prod"].GetDefaults())
+	})
+
+	t.Run("migrates documents written before versioning keeping a backup", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		content := "currentContext: local\ncontexts:\n  local:\n    serverUrl: http://localhost:8080\n  prod:\n    serverUrl: https://maestro.example.com\n"
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return([]byte(content), nil)
+		fs.EXPECT().IsNotExist(nil).Return(false)
+		memFs := afero.NewMemMapFs()
+		fs.EXPECT().MkdirAll(gomock.Any(), ConfigDirPerm).Return(nil).Times(2)
+		fs.EXPECT().OpenFile(gomock.Any(), ConfigFileFlag, ConfigFilePerm).DoAndReturn(func(path string, _ int, _ os.FileMode) (afero.File, error) {
+			return memFs.Create(filepath.Base(path))
+		}).Times(2)
+
+		config, err := ReadConfig(fs)
+
+		require.NoError(t, err)
+		require.Equal(t, CurrentVersion, config.Version)
+		require.Equal(t, "local", config.CurrentContext)
+		require.Len(t, config.Migrations, 1)
+		require.Contains(t, config.Migrations[0], "from version 0 to 1, backup at")
+		written, err := afero.ReadFile(memFs, "config.yaml")
+		require.NoError(t, err)
+		require.Contains(t, string(written), "version: 1\ncurrentContext: local\n")
+		backup, err := afero.ReadFile(memFs, "config.yaml.v0.bak")
+		require.NoError(t, err)
+		require.Equal(t, content, string(backup))
+	})
+
+	t.Run("fails when version is newer than supported", func(t *testing.T) {
+		_, err := readConfig(t, "version: 99\ncontexts: {}\n")
+
+		var configErr *ConfigError
+		require.ErrorAs(t, err, &configErr)
+		require.Contains(t, err.Error(), "version 99 is newer than the supported version 1, upgrade maestro-cli")
+	})
+
+	t.Run("fails on unknown fields", func(t *testing.T) {
+		_, err := readConfig(t, "contexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    serverURL: https://maestro.example.com\n")
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field serverURL not found")
+		require.Contains(t, err.Error(), "remove unknown fields or upgrade maestro-cli")
+	})
+
+	t.Run("fails on invalid settings", func(t *testing.T) {
+		testCases := []struct {
+			Title         string
+			Content       string
+			ExpectedError string
+		}{
+			{
+				Title:         "bad server url",
+				Content:       "version: 1\ncontexts:\n  prod:\n    serverUrl: maestro\n",
+				ExpectedError: "context \"prod\": bad serverUrl \"maestro\"",
+			}, {
+				Title:         "current context does not exist",
+				Content:       "version: 1\ncurrentContext: staging\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n",
+				ExpectedError: "currentContext \"staging\" does not exist",
+			}, {
+				Title:         "client certificate without key",
+				Content:       "version: 1\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    certFile: cert.pem\n",
+				ExpectedError: "context \"prod\": certFile and keyFile must be set together",
+			}, {
+				Title:         "device code flow without device auth url",
+				Content:       "version: 1\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    auth:\n      oauth2:\n        flow: device-code\n        tokenUrl: https://auth.example.com/token\n        clientId: maestro-cli\n",
+				ExpectedError: "context \"prod\": auth.oauth2.deviceAuthUrl is required by the device-code flow",
+			}, {
+				Title:         "more than one auth method",
+				Content:       "version: 1\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    auth:\n      token: token\n      tokenFile: /tmp/token\n",
+				ExpectedError: "context \"prod\": auth must have only one of token, tokenFile, oauth2 or credentialHelper",
+			}, {
+				Title:         "unknown default output",
+				Content:       "version: 1\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    defaults:\n      output: xml\n",
+				ExpectedError: "context \"prod\": bad defaults.output \"xml\", use one of table, wide, json, yaml, name, csv, markdown",
+			}, {
+				Title:         "negative default wait timeout",
+				Content:       "version: 1\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    defaults:\n      waitTimeout: -1m\n",
+				ExpectedError: "context \"prod\": defaults.waitTimeout must be positive",
+			}, {
+				Title:         "unknown transport",
+				Content:       "version: 1\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    transport: websocket\n",
+				ExpectedError: "context \"prod\": bad transport \"websocket\", use one of http, grpc",
+			}, {
+				Title:         "proxy with unix socket server url",
+				Content:       "version: 1\ncontexts:\n  prod:\n    serverUrl: unix:///var/run/maestro.sock\n    proxy: socks5://proxy:1080\n",
+				ExpectedError: "context \"prod\": proxy can not be used with unix socket serverUrl",
+			}, {
+				Title:         "unknown proxy scheme",
+				Content:       "version: 1\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n    proxy: ftp://proxy\n",
+				ExpectedError: "context \"prod\": proxy scheme must be http, https or socks5",
+			},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.Title, func(t *testing.T) {
+				config, err := readConfig(t, testCase.Content)
+				require.NoError(t, err)
+
+				err = config.Check()
+
+				var configErr *ConfigError
+				require.ErrorAs(t, err, &configErr)
+				require.Contains(t, err.Error(), testCase.ExpectedError)
+			})
+		}
+	})
+
+	t.Run("checks only the context in use", func(t *testing.T) {
+		config, err := readConfig(t, "version: 1\ncurrentContext: prod\ncontexts:\n  prod:\n    serverUrl: https://maestro.example.com\n  broken:\n    serverUrl: https://maestro.example.com\n    certFile: cert.pem\n")
+		require.NoError(t, err)
+
+		require.NoError(t, config.CheckContext("prod"))
+		err = config.CheckContext("broken")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `context "broken": certFile and keyFile must be set together, fix the field or remove the context from the file`)
+		require.EqualError(t, config.CheckContext("missing"), `context "missing" not found`)
+	})
+}
+
+func TestMigrateLegacyConfig(t *testing.T) {
+	t.Run("migrates legacy files keeping a backup", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return(nil, os.ErrNotExist)
+		fs.EXPECT().IsNotExist(os.ErrNotExist).Return(true)
+		fs.EXPECT().Glob(gomock.Any()).Return([]string{"/home/user/.maestro/config-local.yaml", "/home/user/.maestro/config-prod.yaml"}, nil)
+		fs.EXPECT().ReadFile("/home/user/.maestro/config-local.yaml").Return([]byte("serverUrl: http://localhost:8080\n"), nil)
+		fs.EXPECT().ReadFile("/home/user/.maestro/config-prod.yaml").Return([]byte("serverUrl: https://maestro.example.com\n"), nil)
+
+		memFs := afero.NewMemMapFs()
+		file, err := memFs.Create("config.yaml")
+		require.NoError(t, err)
+		fs.EXPECT().MkdirAll(gomock.Any(), ConfigDirPerm).Return(nil)
+		fs.EXPECT().OpenFile(gomock.Any(), ConfigFileFlag, ConfigFilePerm).Return(file, nil)
+		fs.EXPECT().Rename("/home/user/.maestro/config-local.yaml", "/home/user/.maestro/config-local.yaml.bak").Return(nil)
+		fs.EXPECT().Rename("/home/user/.maestro/config-prod.yaml", "/home/user/.maestro/config-prod.yaml.bak").Return(nil)
+
+		config, err := ReadConfig(fs)
+
+		require.NoError(t, err)
+		require.Equal(t, "prod", config.CurrentContext)
+		require.Equal(t, []string{"local", "prod"}, config.ContextNames())
+		require.Len(t, config.Migrations, 2)
+
+		written, err := afero.ReadFile(memFs, "config.yaml")
+		require.NoError(t, err)
+		require.Contains(t, string(written), "version: 1\ncurrentContext: prod\n")
+	})
+
+	t.Run("fails when legacy file has unknown fields", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		fs := mocks.NewMockFileSystem(mockCtrl)
+		fs.EXPECT().ReadFile(gomock.Any()).Return(nil, os.ErrNotExist)
+		fs.EXPECT().IsNotExist(os.ErrNotExist).Return(true)
+		fs.EXPECT().Glob(gomock.Any()).Return([]string{"/home/user/.maestro/config-prod.yaml"}, nil)
+		fs.EXPECT().ReadFile("/home/user/.maestro/config-prod.yaml").Return([]byte("serverUrl: https://maestro.example.com\nlegacyField: true\n"), nil)
+
+		_, err := ReadConfig(fs)
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field legacyField not found")
+	})
+}
+
+func TestWriteFile(t *testing.T) {
+	t.Run("writes the config readable only by its owner", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), ".maestro")
+		path := filepath.Join(dir, "config.yaml")
+
+		err := writeFile(NewFileSystem(), path, []byte("version: 1\n"))
+
+		require.NoError(t, err)
+		dirInfo, err := os.Stat(dir)
+		require.NoError(t, err)
+		require.Equal(t, ConfigDirPerm, dirInfo.Mode().Perm())
+		fileInfo, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, ConfigFilePerm, fileInfo.Mode().Perm())
+	})
+
+	t.Run("restricts configs written readable by every user", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, ioutil.WriteFile(path, []byte("version: 1\ncurrentContext: prod\n"), 0644))
+
+		err := writeFile(NewFileSystem(), path, []byte("version: 1\n"))
+
+		require.NoError(t, err)
+		fileInfo, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, ConfigFilePerm, fileInfo.Mode().Perm())
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "version: 1\n", string(content))
+	})
+}
@@ -0,0 +1,112 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialRequest is written as JSON to the credential helper stdin
+type CredentialRequest struct {
+	Context   string `json:"context"`
+	ServerURL string `json:"serverUrl"`
+}
+
+// CredentialResponse is read as JSON from the credential helper stdout, a
+// zero Expiry means the token does not expire
+type CredentialResponse struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"tokenType,omitempty"`
+	Expiry    time.Time `json:"expiry,omitempty"`
+}
+
+// credentialCache keeps the tokens returned by credential helpers for the
+// process lifetime, so each helper runs once per context unless its token
+// expires
+var credentialCache = struct {
+	sync.Mutex
+	tokens map[string]*Token
+}{tokens: map[string]*Token{}}
+
+// CredentialHelperTokenSource obtains tokens from an external executable, like
+// git credential helpers, so they are never stored on the config file. The
+// helper is run with the get argument.
+type CredentialHelperTokenSource struct {
+	command string
+	request CredentialRequest
+}
+
+// NewCredentialHelperTokenSource ctor
+func NewCredentialHelperTokenSource(command string, request CredentialRequest) *CredentialHelperTokenSource {
+	return &CredentialHelperTokenSource{
+		command: command,
+		request: request,
+	}
+}
+
+// Token returns the cached token, running the helper when there is none or it
+// is expired
+func (s *CredentialHelperTokenSource) Token(ctx context.Context) (*Token, error) {
+	credentialCache.Lock()
+	defer credentialCache.Unlock()
+
+	key := strings.Join([]string{s.command, s.request.Context, s.request.ServerURL}, "\x00")
+	if token := credentialCache.tokens[key]; token.Valid() {
+		return token, nil
+	}
+
+	token, err := s.run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	credentialCache.tokens[key] = token
+	return token, nil
+}
+
+func (s *CredentialHelperTokenSource) run(ctx context.Context) (*Token, error) {
+	input, err := json.Marshal(s.request)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.command, "get")
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("error running credential helper %s: %w: %s", s.command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var response CredentialResponse
+	err = json.Unmarshal(stdout.Bytes(), &response)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing credential helper %s output: %w", s.command, err)
+	}
+	if response.Token == "" {
+		return nil, fmt.Errorf("credential helper %s returned no token", s.command)
+	}
+
+	token := &Token{
+		AccessToken: response.Token,
+		TokenType:   response.TokenType,
+		Expiry:      response.Expiry,
+	}
+	if !token.Valid() {
+		return nil, fmt.Errorf("credential helper %s returned a token expired at %s", s.command, response.Expiry.Format(time.RFC3339))
+	}
+	return token, nil
+}
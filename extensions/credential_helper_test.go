@@ -0,0 +1,115 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeHelper writes a fake credential helper that records its arguments,
+// stdin and every call next to it
+func writeHelper(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), "maestro-credentials")
+	content := "#!/bin/sh\necho \"$@\" > \"$0.args\"\ncat > \"$0.input\"\necho called >> \"$0.calls\"\n" + script
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0700))
+	return path
+}
+
+func readHelperFile(t *testing.T, helper, suffix string) string {
+	bts, err := ioutil.ReadFile(helper + suffix)
+	require.NoError(t, err)
+	return string(bts)
+}
+
+func TestCredentialHelperTokenSource(t *testing.T) {
+	var authorization string
+	maestro := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer maestro.Close()
+
+	t.Run("runs the helper once and caches its token", func(t *testing.T) {
+		expiry := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		helper := writeHelper(t, fmt.Sprintf(`echo '{"token":"helper-token","expiry":"%s"}'`, expiry))
+		config := &ContextConfig{ServerURL: maestro.URL, Auth: &AuthConfig{CredentialHelper: helper}}
+
+		tokenSource, err := NewTokenSource("cached", config, nil)
+		require.NoError(t, err)
+		client, err := NewClient(config, tokenSource)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, _, err = client.Get(context.Background(), maestro.URL, "")
+			require.NoError(t, err)
+			require.Equal(t, "Bearer helper-token", authorization)
+		}
+
+		otherTokenSource, err := NewTokenSource("cached", config, nil)
+		require.NoError(t, err)
+		token, err := otherTokenSource.Token(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "helper-token", token.AccessToken)
+
+		require.Equal(t, "get\n", readHelperFile(t, helper, ".args"))
+		require.JSONEq(t, fmt.Sprintf(`{"context":"cached","serverUrl":"%s"}`, maestro.URL), readHelperFile(t, helper, ".input"))
+		require.Equal(t, 1, strings.Count(readHelperFile(t, helper, ".calls"), "called"))
+	})
+
+	t.Run("runs the helper for each context", func(t *testing.T) {
+		helper := writeHelper(t, `echo '{"token":"helper-token","tokenType":"Token"}'`)
+
+		for _, contextName := range []string{"first", "second"} {
+			tokenSource, err := NewTokenSource(contextName, &ContextConfig{Auth: &AuthConfig{CredentialHelper: helper}}, nil)
+			require.NoError(t, err)
+			token, err := tokenSource.Token(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, "Token helper-token", token.AuthorizationHeader())
+		}
+
+		require.Equal(t, 2, strings.Count(readHelperFile(t, helper, ".calls"), "called"))
+	})
+
+	t.Run("fails when the helper fails", func(t *testing.T) {
+		helper := writeHelper(t, "echo 'not logged in' >&2\nexit 1")
+
+		_, err := NewCredentialHelperTokenSource(helper, CredentialRequest{Context: "failing"}).Token(context.Background())
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error running credential helper")
+		require.Contains(t, err.Error(), "not logged in")
+	})
+
+	t.Run("fails when the helper returns no token", func(t *testing.T) {
+		helper := writeHelper(t, `echo '{}'`)
+
+		_, err := NewCredentialHelperTokenSource(helper, CredentialRequest{Context: "empty"}).Token(context.Background())
+
+		require.Error(t, err)
+		require.Equal(t, fmt.Sprintf("credential helper %s returned no token", helper), err.Error())
+	})
+
+	t.Run("fails when the helper returns an expired token", func(t *testing.T) {
+		expiry := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		helper := writeHelper(t, fmt.Sprintf(`echo '{"token":"helper-token","expiry":"%s"}'`, expiry))
+
+		_, err := NewCredentialHelperTokenSource(helper, CredentialRequest{Context: "expired"}).Token(context.Background())
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "returned a token expired at "+expiry)
+	})
+}
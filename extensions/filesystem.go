@@ -8,7 +8,9 @@
 package extensions
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/afero"
 )
@@ -29,6 +31,11 @@ func (m *FileSystem) Create(name string) (afero.File, error) {
 	return os.Create(name)
 }
 
+//OpenFile opens a file, creating it with perm if flag has os.O_CREATE
+func (m *FileSystem) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
 //IsNotExist returns true if err if of type FileNotExists
 func (m *FileSystem) IsNotExist(err error) bool {
 	return os.IsNotExist(err)
@@ -38,3 +45,18 @@ func (m *FileSystem) IsNotExist(err error) bool {
 func (m *FileSystem) Stat(name string) (os.FileInfo, error) {
 	return os.Stat(name)
 }
+
+//ReadFile reads the whole named file
+func (m *FileSystem) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
+
+//Glob returns the names of all files matching pattern
+func (m *FileSystem) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+//Rename renames a file
+func (m *FileSystem) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
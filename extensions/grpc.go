@@ -0,0 +1,228 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// TransportHTTP calls maestro through its HTTP gateway, the default
+	TransportHTTP = "http"
+	// TransportGRPC calls the maestro gRPC services directly
+	TransportGRPC = "grpc"
+)
+
+// Transports are the values accepted by transport
+var Transports = []string{TransportHTTP, TransportGRPC}
+
+// GetTransport returns the transport of the context, TransportHTTP unless
+// set
+func (c *ContextConfig) GetTransport() string {
+	if c.Transport == "" {
+		return TransportHTTP
+	}
+	return c.Transport
+}
+
+// NewGRPCConn connects to the gRPC services at the host of the context
+// serverUrl, over TLS for https URLs and plaintext for http and unix socket
+// ones. Calls send the token of tokenSource, that may be nil, have the
+// context timeout and are retried like the HTTP requests.
+func NewGRPCConn(config *ContextConfig, tokenSource TokenSource, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	address, err := ParseServerURL(config.ServerURL)
+	if err != nil {
+		return nil, err
+	}
+	serverURL, err := url.Parse(address.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("bad serverUrl %q: %w", config.ServerURL, err)
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	port := "80"
+	if serverURL.Scheme == "https" {
+		tlsConfig, err := NewTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		transportCredentials = credentials.NewTLS(tlsConfig)
+		port = "443"
+	}
+	target := serverURL.Host
+	switch {
+	case address.Socket != "":
+		target = "unix:" + address.Socket
+	case serverURL.Port() == "":
+		target = net.JoinHostPort(serverURL.Hostname(), port)
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithChainUnaryInterceptor(retryInterceptor(config.Retry, sleep), timeoutInterceptor(timeout)),
+	}
+	if tokenSource != nil {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(&tokenCredentials{tokenSource: tokenSource}))
+	}
+	return grpc.Dial(target, append(dialOptions, opts...)...)
+}
+
+// WithGRPCTrace writes every call and its response to out, like the traces
+// of the HTTP transport
+func WithGRPCTrace(out io.Writer, trace *TraceConfig) grpc.DialOption {
+	tracer := &grpcTracer{out: out, redactFields: trace.GetRedactFields()}
+	return grpc.WithChainUnaryInterceptor(tracer.intercept)
+}
+
+// timeoutInterceptor gives every call its own deadline, ctx still cancels
+// them
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// idempotentGRPCMethods are the methods the HTTP gateway maps to GET and PUT
+// requests, the ones retried by the HTTP transport. Add rooms is not, as the
+// gRPC services take no idempotency key.
+var idempotentGRPCMethods = map[string]bool{
+	"ListSchedulers":       true,
+	"GetScheduler":         true,
+	"GetSchedulerVersions": true,
+	"GetSchedulersInfo":    true,
+	"SwitchActiveVersion":  true,
+	"ListOperations":       true,
+	"GetOperation":         true,
+	"UpdateRoomWithPing":   true,
+	"UpdateRoomStatus":     true,
+}
+
+// retryInterceptor retries the calls of idempotentGRPCMethods failed with the
+// codes of the statuses the HTTP transport retries, with the same backoff.
+// It runs before timeoutInterceptor, so every attempt has its own deadline.
+func retryInterceptor(retry *RetryConfig, sleep func(context.Context, time.Duration) error) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !idempotentGRPCMethods[path.Base(method)] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		start := time.Now()
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || ctx.Err() != nil || !shouldRetryGRPC(err) || attempt >= retry.GetMaxAttempts() {
+				return err
+			}
+			wait := backoff(attempt, 0)
+			if time.Since(start)+wait > retry.GetMaxElapsedTime() {
+				return err
+			}
+			if sleepErr := sleep(ctx, wait); sleepErr != nil {
+				return sleepErr
+			}
+		}
+	}
+}
+
+// shouldRetryGRPC is shouldRetry for gRPC calls, the gateway responds 503 and
+// 502 for Unavailable, 429 for ResourceExhausted and 504 for DeadlineExceeded.
+// Failed TLS handshakes are Unavailable too, but not transient.
+func shouldRetryGRPC(err error) bool {
+	s := status.Convert(err)
+	switch s.Code() {
+	case codes.Unavailable:
+		return !strings.Contains(s.Message(), "authentication handshake failed")
+	case codes.ResourceExhausted, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// tokenCredentials sends the token on the authorization metadata,
+// refreshing it if needed
+type tokenCredentials struct {
+	tokenSource TokenSource
+}
+
+func (t *tokenCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := t.tokenSource.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting auth token: %w", err)
+	}
+	return map[string]string{"authorization": token.AuthorizationHeader()}, nil
+}
+
+// RequireTransportSecurity is false, so plaintext connections to local
+// servers are authenticated too
+func (t *tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// grpcTracer writes the calls of a connection to out
+type grpcTracer struct {
+	out          io.Writer
+	redactFields []string
+	mu           sync.Mutex
+}
+
+func (t *grpcTracer) intercept(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	latency := time.Since(start)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var b strings.Builder
+	fmt.Fprintf(&b, "> %s %s\n", cc.Target(), method)
+	t.writeMessage(&b, "> ", req)
+	if err != nil {
+		fmt.Fprintf(&b, "< %s after %s: %s\n", status.Code(err), latency.Round(time.Millisecond), status.Convert(err).Message())
+	} else {
+		fmt.Fprintf(&b, "< OK (%s)\n", latency.Round(time.Millisecond))
+		t.writeMessage(&b, "< ", reply)
+	}
+	fmt.Fprint(t.out, b.String())
+	return err
+}
+
+func (t *grpcTracer) writeMessage(b *strings.Builder, prefix string, message interface{}) {
+	m, ok := message.(proto.Message)
+	if !ok {
+		return
+	}
+	body, err := protojson.Marshal(m)
+	if err != nil || len(body) == 0 || string(body) == "{}" {
+		return
+	}
+	fmt.Fprintf(b, "%s\n%s%s\n", prefix, prefix, RedactBody(body, t.redactFields))
+}
@@ -0,0 +1,156 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// schedulersServer responds with the authorization metadata and deadline of
+// the call
+type schedulersServer struct {
+	v1.UnimplementedSchedulersServiceServer
+	authorization []string
+	deadline      time.Time
+}
+
+func (s *schedulersServer) ListSchedulers(ctx context.Context, _ *v1.ListSchedulersRequest) (*v1.ListSchedulersResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authorization = md.Get("authorization")
+	s.deadline, _ = ctx.Deadline()
+	return &v1.ListSchedulersResponse{Schedulers: []*v1.SchedulerWithoutSpec{{Name: "scheduler"}}}, nil
+}
+
+func newBufconnDialer(t *testing.T, server *schedulersServer) grpc.DialOption {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	v1.RegisterSchedulersServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+	return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	})
+}
+
+func TestNewGRPCConn(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends the token and the timeout", func(t *testing.T) {
+		server := &schedulersServer{}
+		config := &ContextConfig{ServerURL: "http://maestro", Timeout: time.Minute}
+		conn, err := NewGRPCConn(config, &staticTokenSource{token: &Token{AccessToken: "token"}}, newBufconnDialer(t, server))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = v1.NewSchedulersServiceClient(conn).ListSchedulers(ctx, &v1.ListSchedulersRequest{})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"Bearer token"}, server.authorization)
+		require.WithinDuration(t, time.Now().Add(time.Minute), server.deadline, 5*time.Second)
+		require.Equal(t, "maestro:80", conn.Target())
+	})
+
+	t.Run("traces the calls", func(t *testing.T) {
+		server := &schedulersServer{}
+		out := new(bytes.Buffer)
+		config := &ContextConfig{ServerURL: "http://maestro:9000"}
+		conn, err := NewGRPCConn(config, nil, newBufconnDialer(t, server), WithGRPCTrace(out, nil))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = v1.NewSchedulersServiceClient(conn).ListSchedulers(ctx, &v1.ListSchedulersRequest{Game: "game"})
+
+		require.NoError(t, err)
+		require.Empty(t, server.authorization)
+		require.Contains(t, out.String(), "> maestro:9000 /api.v1.SchedulersService/ListSchedulers\n> \n> {\"game\":\"game\"}\n< OK (")
+		require.Contains(t, out.String(), `"name":"scheduler"`)
+	})
+
+	t.Run("dials unix sockets", func(t *testing.T) {
+		conn, err := NewGRPCConn(&ContextConfig{ServerURL: "unix:///var/run/maestro.sock"}, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		require.Equal(t, "unix:/var/run/maestro.sock", conn.Target())
+	})
+
+	t.Run("fails on bad TLS settings", func(t *testing.T) {
+		_, err := NewGRPCConn(&ContextConfig{ServerURL: "https://maestro", CAFile: "missing.pem"}, nil)
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error reading CA file")
+	})
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	ctx := context.Background()
+	noSleep := func(context.Context, time.Duration) error { return nil }
+	// invoker fails with the errors, then succeeds
+	invoker := func(calls *int, errs ...error) grpc.UnaryInvoker {
+		return func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+			*calls++
+			if *calls <= len(errs) {
+				return errs[*calls-1]
+			}
+			return nil
+		}
+	}
+
+	t.Run("retries idempotent calls on transient codes", func(t *testing.T) {
+		calls := 0
+		interceptor := retryInterceptor(&RetryConfig{MaxAttempts: 3}, noSleep)
+
+		err := interceptor(ctx, "/api.v1.SchedulersService/ListSchedulers", nil, nil, nil,
+			invoker(&calls, status.Error(codes.Unavailable, "restarting"), status.Error(codes.ResourceExhausted, "rate limited")))
+
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("stops at the max attempts", func(t *testing.T) {
+		calls := 0
+		interceptor := retryInterceptor(&RetryConfig{MaxAttempts: 2}, noSleep)
+		unavailable := status.Error(codes.Unavailable, "restarting")
+
+		err := interceptor(ctx, "/api.v1.OperationsService/GetOperation", nil, nil, nil, invoker(&calls, unavailable, unavailable, unavailable))
+
+		require.Equal(t, unavailable, err)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("does not retry other calls and codes", func(t *testing.T) {
+		for _, testCase := range []struct {
+			Method string
+			Err    error
+		}{
+			{Method: "/api.v1.SchedulersService/AddRooms", Err: status.Error(codes.Unavailable, "restarting")},
+			{Method: "/api.v1.SchedulersService/GetScheduler", Err: status.Error(codes.NotFound, "not found")},
+			{Method: "/api.v1.SchedulersService/GetScheduler", Err: status.Error(codes.Unavailable, "connection error: desc = \"transport: authentication handshake failed: x509: certificate signed by unknown authority\"")},
+		} {
+			calls := 0
+			interceptor := retryInterceptor(nil, noSleep)
+
+			err := interceptor(ctx, testCase.Method, nil, nil, nil, invoker(&calls, testCase.Err, testCase.Err))
+
+			require.Equal(t, testCase.Err, err)
+			require.Equal(t, 1, calls, testCase.Method)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/topfreegames/maestro-cli/interfaces"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// CurrentVersion is the config file version written by this maestro-cli.
+// Version 0 are the config-<context>.yaml files, one per context, and the
+// config.yaml documents written before versioning. Version 1 is the versioned
+// config.yaml document holding every context.
+const CurrentVersion = 1
+
+// migrations upgrade a config document, migrations[i] upgrades it from
+// version i to i+1
+var migrations = []func(doc map[string]interface{}) error{
+	migrateV0ToV1,
+}
+
+// documentVersion returns the version of a config.yaml document, documents
+// without version were written before versioning and are version 0
+func documentVersion(doc map[string]interface{}) (int, error) {
+	version, ok := doc["version"]
+	if !ok {
+		return 0, nil
+	}
+	v, ok := version.(int)
+	if !ok || v < 0 {
+		return 0, fmt.Errorf("bad version %v", version)
+	}
+	return v, nil
+}
+
+// migrate runs the migrations chain, upgrading doc from version to
+// CurrentVersion
+func migrate(doc map[string]interface{}, version int) error {
+	for ; version < CurrentVersion; version++ {
+		err := migrations[version](doc)
+		if err != nil {
+			return fmt.Errorf("error migrating from version %d to %d: %w", version, version+1, err)
+		}
+		doc["version"] = version + 1
+	}
+	return nil
+}
+
+// migrateV0ToV1 selects "prod", the default context of version 0, when no
+// context is selected so existing setups behave the same after the upgrade
+func migrateV0ToV1(doc map[string]interface{}) error {
+	contexts, ok := doc["contexts"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if current, _ := doc["currentContext"].(string); current != "" {
+		return nil
+	}
+	if _, ok := contexts["prod"]; ok {
+		doc["currentContext"] = "prod"
+	}
+	return nil
+}
+
+// migrateLegacyConfig builds a Config from the config-<context>.yaml files
+// written by previous maestro-cli versions. The result is written to
+// config.yaml and the legacy files are renamed to <file>.bak.
+func migrateLegacyConfig(fs interfaces.FileSystem) (*Config, error) {
+	dir, err := getDirPath()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := fs.Glob(filepath.Join(dir, "config-*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return NewConfig(), nil
+	}
+
+	contexts := map[string]interface{}{}
+	for _, path := range paths {
+		bts, err := fs.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		contextDoc := map[string]interface{}{}
+		err = yaml.Unmarshal(bts, &contextDoc)
+		if err != nil {
+			return nil, &ConfigError{Path: path, Err: err, Hint: "fix the YAML syntax of the file"}
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "config-"), ".yaml")
+		contexts[name] = contextDoc
+	}
+
+	doc := map[string]interface{}{"contexts": contexts}
+	err = migrate(doc, 0)
+	if err != nil {
+		return nil, &ConfigError{Path: dir, Err: err, Hint: "fix the legacy config files or remove them and run maestro-cli init again"}
+	}
+	c, err := decodeConfig(filepath.Join(dir, "config-*.yaml"), doc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Write(fs)
+	if err != nil {
+		return nil, fmt.Errorf("error writing migrated config file: %w", err)
+	}
+	for _, path := range paths {
+		err = fs.Rename(path, path+".bak")
+		if err != nil {
+			return nil, fmt.Errorf("error backing up legacy config file: %w", err)
+		}
+		c.Migrations = append(c.Migrations, fmt.Sprintf("migrated %s to version %d, backup at %s.bak", path, CurrentVersion, path))
+	}
+	return c, nil
+}
@@ -0,0 +1,310 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// OAuth2ClientCredentials is the flow used by services, authenticating
+	// with a client id and secret
+	OAuth2ClientCredentials = "client-credentials"
+	// OAuth2DeviceCode is the flow used by people, authenticating on a
+	// browser with a code shown by maestro-cli login
+	OAuth2DeviceCode = "device-code"
+
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+// ErrLoginRequired is returned when there is no valid token and one can only
+// be obtained interactively
+var ErrLoginRequired = errors.New("login required, run maestro-cli login")
+
+// OAuth2Config holds the OAuth2 settings of a context and the token cached by
+// maestro-cli login
+type OAuth2Config struct {
+	Flow          string   `yaml:"flow"`
+	TokenURL      string   `yaml:"tokenUrl"`
+	DeviceAuthURL string   `yaml:"deviceAuthUrl,omitempty"`
+	ClientID      string   `yaml:"clientId"`
+	ClientSecret  string   `yaml:"clientSecret,omitempty"`
+	Scopes        []string `yaml:"scopes,omitempty"`
+	CachedToken   *Token   `yaml:"cachedToken,omitempty"`
+}
+
+// OAuth2TokenSource obtains and refreshes tokens from an OAuth2 server
+type OAuth2TokenSource struct {
+	config       *OAuth2Config
+	client       *http.Client
+	onRefresh    func(*Token)
+	pollInterval time.Duration
+	mu           sync.Mutex
+}
+
+// NewOAuth2TokenSource ctor
+func NewOAuth2TokenSource(config *OAuth2Config, onRefresh func(*Token)) *OAuth2TokenSource {
+	return &OAuth2TokenSource{
+		config:       config,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		onRefresh:    onRefresh,
+		pollInterval: 5 * time.Second,
+	}
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type deviceAuthResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// Token returns the cached token, refreshing it when it is expired
+func (s *OAuth2TokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config.CachedToken.Valid() {
+		return s.config.CachedToken, nil
+	}
+
+	if s.config.CachedToken != nil && s.config.CachedToken.RefreshToken != "" {
+		token, err := s.refresh(ctx, s.config.CachedToken.RefreshToken)
+		if err == nil {
+			return s.cache(token), nil
+		}
+		if s.config.Flow != OAuth2ClientCredentials {
+			return nil, fmt.Errorf("error refreshing token: %w", err)
+		}
+	}
+
+	if s.config.Flow != OAuth2ClientCredentials {
+		return nil, ErrLoginRequired
+	}
+
+	token, err := s.clientCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.cache(token), nil
+}
+
+// Login obtains a new token, ignoring the cached one. On the device code flow
+// the instructions to authorize maestro-cli are written to out, and polling
+// stops when ctx is done.
+func (s *OAuth2TokenSource) Login(ctx context.Context, out io.Writer) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var token *Token
+	var err error
+	switch s.config.Flow {
+	case OAuth2ClientCredentials:
+		token, err = s.clientCredentials(ctx)
+	case OAuth2DeviceCode:
+		token, err = s.deviceCode(ctx, out)
+	default:
+		err = fmt.Errorf("unknown oauth2 flow %q, use %s or %s", s.config.Flow, OAuth2ClientCredentials, OAuth2DeviceCode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.cache(token), nil
+}
+
+func (s *OAuth2TokenSource) cache(token *Token) *Token {
+	s.config.CachedToken = token
+	if s.onRefresh != nil {
+		s.onRefresh(token)
+	}
+	return token
+}
+
+func (s *OAuth2TokenSource) clientCredentials(ctx context.Context) (*Token, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.config.ClientID},
+		"client_secret": {s.config.ClientSecret},
+	}
+	s.addScopes(form)
+
+	response, err := s.requestToken(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, response.err()
+	}
+	return response.token(), nil
+}
+
+func (s *OAuth2TokenSource) refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {s.config.ClientID},
+	}
+	if s.config.ClientSecret != "" {
+		form.Set("client_secret", s.config.ClientSecret)
+	}
+
+	response, err := s.requestToken(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, response.err()
+	}
+
+	token := response.token()
+	// servers are not required to rotate the refresh token
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+func (s *OAuth2TokenSource) deviceCode(ctx context.Context, out io.Writer) (*Token, error) {
+	if s.config.DeviceAuthURL == "" {
+		return nil, errors.New("oauth2 deviceAuthUrl is required by the device-code flow")
+	}
+
+	form := url.Values{"client_id": {s.config.ClientID}}
+	s.addScopes(form)
+
+	var deviceAuth deviceAuthResponse
+	status, err := s.postForm(ctx, s.config.DeviceAuthURL, form, &deviceAuth)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting device code: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("device code response not ok, status: %s", http.StatusText(status))
+	}
+
+	if deviceAuth.VerificationURIComplete != "" {
+		fmt.Fprintf(out, "Open %s to authorize maestro-cli\n", deviceAuth.VerificationURIComplete)
+	} else {
+		fmt.Fprintf(out, "Open %s and enter the code %s to authorize maestro-cli\n", deviceAuth.VerificationURI, deviceAuth.UserCode)
+	}
+
+	interval := s.pollInterval
+	if deviceAuth.Interval > 0 {
+		interval = time.Duration(deviceAuth.Interval) * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(deviceAuth.ExpiresIn) * time.Second)
+
+	pollForm := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {deviceAuth.DeviceCode},
+		"client_id":   {s.config.ClientID},
+	}
+	for deviceAuth.ExpiresIn == 0 || time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		response, err := s.requestToken(ctx, pollForm)
+		if err != nil {
+			return nil, err
+		}
+		switch response.Error {
+		case "":
+			return response.token(), nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return nil, response.err()
+		}
+	}
+
+	return nil, errors.New("device code expired before authorization, run maestro-cli login again")
+}
+
+func (s *OAuth2TokenSource) addScopes(form url.Values) {
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+}
+
+func (s *OAuth2TokenSource) requestToken(ctx context.Context, form url.Values) (*tokenResponse, error) {
+	response := &tokenResponse{}
+	status, err := s.postForm(ctx, s.config.TokenURL, form, response)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting token: %w", err)
+	}
+	if status != http.StatusOK && response.Error == "" {
+		return nil, fmt.Errorf("token response not ok, status: %s", http.StatusText(status))
+	}
+	return response, nil
+}
+
+func (s *OAuth2TokenSource) postForm(ctx context.Context, endpoint string, form url.Values, response interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return 0, err
+	}
+	if err = json.Unmarshal(body, response); err != nil && res.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("error parsing response body: %w", err)
+	}
+	return res.StatusCode, nil
+}
+
+func (r *tokenResponse) token() *Token {
+	token := &Token{
+		AccessToken:  r.AccessToken,
+		TokenType:    r.TokenType,
+		RefreshToken: r.RefreshToken,
+	}
+	if r.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return token
+}
+
+func (r *tokenResponse) err() error {
+	if r.ErrorDescription != "" {
+		return fmt.Errorf("oauth2 error %s: %s", r.Error, r.ErrorDescription)
+	}
+	return fmt.Errorf("oauth2 error %s", r.Error)
+}
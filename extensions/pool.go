@@ -0,0 +1,88 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultPoolMaxIdleConns is the idle connections kept by contexts
+	// without pool.maxIdleConns
+	DefaultPoolMaxIdleConns = 100
+	// DefaultPoolMaxIdleConnsPerHost is the idle connections kept to the
+	// maestro server by contexts without pool.maxIdleConnsPerHost
+	DefaultPoolMaxIdleConnsPerHost = 16
+	// DefaultPoolIdleConnTimeout is how long contexts without
+	// pool.idleConnTimeout keep idle connections
+	DefaultPoolIdleConnTimeout = 90 * time.Second
+)
+
+// PoolConfig holds the keep-alive connections reused by the requests
+type PoolConfig struct {
+	MaxIdleConns        int           `yaml:"maxIdleConns,omitempty"`
+	MaxIdleConnsPerHost int           `yaml:"maxIdleConnsPerHost,omitempty"`
+	IdleConnTimeout     time.Duration `yaml:"idleConnTimeout,omitempty"`
+	// DisableHTTP2 keeps HTTP/1.1 on servers that negotiate HTTP/2
+	DisableHTTP2 bool `yaml:"disableHttp2,omitempty"`
+}
+
+// GetMaxIdleConns returns the configured idle connections or the default
+func (p *PoolConfig) GetMaxIdleConns() int {
+	if p == nil || p.MaxIdleConns == 0 {
+		return DefaultPoolMaxIdleConns
+	}
+	return p.MaxIdleConns
+}
+
+// GetMaxIdleConnsPerHost returns the configured idle connections per host or
+// the default
+func (p *PoolConfig) GetMaxIdleConnsPerHost() int {
+	if p == nil || p.MaxIdleConnsPerHost == 0 {
+		return DefaultPoolMaxIdleConnsPerHost
+	}
+	return p.MaxIdleConnsPerHost
+}
+
+// GetIdleConnTimeout returns the configured idle timeout or the default
+func (p *PoolConfig) GetIdleConnTimeout() time.Duration {
+	if p == nil || p.IdleConnTimeout == 0 {
+		return DefaultPoolIdleConnTimeout
+	}
+	return p.IdleConnTimeout
+}
+
+// newTransport returns the transport of the context, connections are kept
+// alive, HTTP/2 is negotiated over TLS and gzip responses are decompressed.
+// Unix socket servers are dialed whatever the request host.
+func newTransport(config *ContextConfig, tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.DisableKeepAlives = false
+	transport.DisableCompression = false
+	transport.MaxIdleConns = config.Pool.GetMaxIdleConns()
+	transport.MaxIdleConnsPerHost = config.Pool.GetMaxIdleConnsPerHost()
+	transport.IdleConnTimeout = config.Pool.GetIdleConnTimeout()
+	// a custom TLS config disables HTTP/2 unless it is forced
+	transport.ForceAttemptHTTP2 = true
+	if config.Pool != nil && config.Pool.DisableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	address, err := ParseServerURL(config.ServerURL)
+	switch {
+	case err == nil && address.Socket != "":
+		transport.DialContext = dialSocket(address.Socket)
+		transport.Proxy = nil
+	case config.Proxy != "":
+		transport.Proxy = proxyFunc(config.Proxy)
+	}
+	return transport
+}
@@ -0,0 +1,129 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingServer returns a TLS server counting the connections opened to
+// it and the HTTP version of the last request
+func newCountingServer(t testing.TB, http2 bool) (*httptest.Server, *int64, *int64) {
+	var connections, protoMajor int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.StoreInt64(&protoMajor, int64(r.ProtoMajor))
+		w.Write([]byte(`{"schedulers":[]}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&connections, 1)
+		}
+	}
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.EnableHTTP2 = http2
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server, &connections, &protoMajor
+}
+
+func TestClientConnections(t *testing.T) {
+	t.Run("reuses connections", func(t *testing.T) {
+		server, connections, _ := newCountingServer(t, false)
+		client, err := NewClient(&ContextConfig{InsecureSkipVerify: true}, nil)
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			_, _, err = client.Put(context.Background(), server.URL, `{"version":"v2"}`)
+			require.NoError(t, err)
+			_, _, err = client.Delete(context.Background(), server.URL)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, int64(1), atomic.LoadInt64(connections))
+	})
+
+	t.Run("negotiates HTTP/2", func(t *testing.T) {
+		server, _, protoMajor := newCountingServer(t, true)
+		client, err := NewClient(&ContextConfig{InsecureSkipVerify: true}, nil)
+		require.NoError(t, err)
+
+		_, _, err = client.Get(context.Background(), server.URL, "")
+
+		require.NoError(t, err)
+		require.Equal(t, int64(2), atomic.LoadInt64(protoMajor))
+	})
+
+	t.Run("keeps HTTP/1.1 when HTTP/2 is disabled", func(t *testing.T) {
+		server, _, protoMajor := newCountingServer(t, true)
+		client, err := NewClient(&ContextConfig{InsecureSkipVerify: true, Pool: &PoolConfig{DisableHTTP2: true}}, nil)
+		require.NoError(t, err)
+
+		_, _, err = client.Get(context.Background(), server.URL, "")
+
+		require.NoError(t, err)
+		require.Equal(t, int64(1), atomic.LoadInt64(protoMajor))
+	})
+
+	t.Run("decompresses gzip responses", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte(`{"schedulers":[]}`))
+			gz.Close()
+		}))
+		defer server.Close()
+		client, err := NewClient(&ContextConfig{}, nil)
+		require.NoError(t, err)
+
+		body, _, err := client.Get(context.Background(), server.URL, "")
+
+		require.NoError(t, err)
+		require.Equal(t, `{"schedulers":[]}`, string(body))
+	})
+}
+
+// BenchmarkClient compares the pooled connections with a new connection per
+// request, as sent by previous versions
+func BenchmarkClient(b *testing.B) {
+	benchmarks := []struct {
+		name      string
+		keepAlive bool
+	}{
+		{name: "keep-alive", keepAlive: true},
+		{name: "new connection per request", keepAlive: false},
+	}
+
+	for _, benchmark := range benchmarks {
+		b.Run(benchmark.name, func(b *testing.B) {
+			server, connections, _ := newCountingServer(b, false)
+			client, err := NewClient(&ContextConfig{InsecureSkipVerify: true}, nil)
+			require.NoError(b, err)
+			client.client.Transport.(*http.Transport).DisableKeepAlives = !benchmark.keepAlive
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _, err := client.Get(context.Background(), server.URL, "")
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(atomic.LoadInt64(connections))/float64(b.N), "conns/op")
+		})
+	}
+}
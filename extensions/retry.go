@@ -0,0 +1,103 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"crypto/x509"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the attempts made by contexts without
+	// retry.maxAttempts, including the first one
+	DefaultRetryMaxAttempts = 4
+	// DefaultRetryMaxElapsedTime is how long requests are retried on
+	// contexts without retry.maxElapsedTime
+	DefaultRetryMaxElapsedTime = time.Minute
+
+	retryInitialInterval = 500 * time.Millisecond
+	retryMaxInterval     = 15 * time.Second
+)
+
+// RetryConfig holds how requests failed by transient errors are retried.
+// Only idempotent requests are retried.
+type RetryConfig struct {
+	MaxAttempts    int           `yaml:"maxAttempts,omitempty"`
+	MaxElapsedTime time.Duration `yaml:"maxElapsedTime,omitempty"`
+}
+
+// GetMaxAttempts returns the configured attempts or the default
+func (r *RetryConfig) GetMaxAttempts() int {
+	if r == nil || r.MaxAttempts == 0 {
+		return DefaultRetryMaxAttempts
+	}
+	return r.MaxAttempts
+}
+
+// GetMaxElapsedTime returns the configured max elapsed time or the default
+func (r *RetryConfig) GetMaxElapsedTime() time.Duration {
+	if r == nil || r.MaxElapsedTime == 0 {
+		return DefaultRetryMaxElapsedTime
+	}
+	return r.MaxElapsedTime
+}
+
+// shouldRetry returns true for connection errors and the statuses returned
+// while maestro or its gateway are overloaded or restarting. Certificate
+// errors are not transient.
+func shouldRetry(status int, err error) bool {
+	if err != nil {
+		var unknownAuthority x509.UnknownAuthorityError
+		var hostname x509.HostnameError
+		var invalid x509.CertificateInvalidError
+		return !errors.As(err, &unknownAuthority) && !errors.As(err, &hostname) && !errors.As(err, &invalid)
+	}
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter parses the Retry-After header of 429 and 503 responses, in
+// seconds or as an HTTP date
+func retryAfter(res *http.Response) time.Duration {
+	if res == nil || (res.StatusCode != http.StatusTooManyRequests && res.StatusCode != http.StatusServiceUnavailable) {
+		return 0
+	}
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// backoff returns how long to wait before the next attempt, doubling the
+// interval on every attempt with jitter so clients do not retry in lockstep.
+// The server Retry-After takes precedence.
+func backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	interval := retryInitialInterval << uint(attempt-1)
+	if interval > retryMaxInterval || interval <= 0 {
+		interval = retryMaxInterval
+	}
+	return interval/2 + time.Duration(rand.Int63n(int64(interval/2)+1))
+}
@@ -0,0 +1,226 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRetry(t *testing.T) {
+	// newServer responds with the statuses in order, then with 200
+	newServer := func(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *[]string) {
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(body))
+			for name, values := range header {
+				w.Header()[name] = values
+			}
+			if len(bodies) <= len(statuses) {
+				w.WriteHeader(statuses[len(bodies)-1])
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server, &bodies
+	}
+	newClient := func(t *testing.T, config *ContextConfig) (*Client, *[]time.Duration) {
+		var sleeps []time.Duration
+		client, err := NewClient(config, nil)
+		require.NoError(t, err)
+		client.sleep = func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		}
+		return client, &sleeps
+	}
+
+	t.Run("retries idempotent requests on transient failures", func(t *testing.T) {
+		server, bodies := newServer(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway)
+		client, sleeps := newClient(t, &ContextConfig{})
+
+		_, status, err := client.Put(context.Background(), server.URL, `{"version":"v2"}`)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{`{"version":"v2"}`, `{"version":"v2"}`, `{"version":"v2"}`}, *bodies)
+		require.Len(t, *sleeps, 2)
+		require.True(t, (*sleeps)[0] >= retryInitialInterval/2 && (*sleeps)[0] <= retryInitialInterval)
+		require.True(t, (*sleeps)[1] >= retryInitialInterval && (*sleeps)[1] <= 2*retryInitialInterval)
+	})
+
+	t.Run("does not retry post requests", func(t *testing.T) {
+		server, bodies := newServer(t, nil, http.StatusServiceUnavailable)
+		client, sleeps := newClient(t, &ContextConfig{})
+
+		_, status, err := client.Post(context.Background(), server.URL, "{}")
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Len(t, *bodies, 1)
+		require.Empty(t, *sleeps)
+	})
+
+	t.Run("retries post requests with an idempotency key", func(t *testing.T) {
+		var keys []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			if len(keys) == 1 {
+				w.WriteHeader(http.StatusGatewayTimeout)
+			}
+		}))
+		defer server.Close()
+		client, _ := newClient(t, &ContextConfig{})
+
+		_, status, err := client.IdempotentPost(context.Background(), server.URL, "{}", "the-key")
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{"the-key", "the-key"}, keys)
+	})
+
+	t.Run("waits for the Retry-After of the server", func(t *testing.T) {
+		server, _ := newServer(t, http.Header{"Retry-After": {"7"}}, http.StatusTooManyRequests)
+		client, sleeps := newClient(t, &ContextConfig{})
+
+		_, status, err := client.Get(context.Background(), server.URL, "")
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []time.Duration{7 * time.Second}, *sleeps)
+	})
+
+	t.Run("does not retry other failures", func(t *testing.T) {
+		server, bodies := newServer(t, nil, http.StatusNotFound)
+		client, _ := newClient(t, &ContextConfig{})
+
+		_, status, err := client.Delete(context.Background(), server.URL)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, status)
+		require.Len(t, *bodies, 1)
+	})
+
+	t.Run("stops after max attempts", func(t *testing.T) {
+		server, bodies := newServer(t, nil, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		client, _ := newClient(t, &ContextConfig{Retry: &RetryConfig{MaxAttempts: 2}})
+
+		_, status, err := client.Get(context.Background(), server.URL, "")
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadGateway, status)
+		require.Len(t, *bodies, 2)
+	})
+
+	t.Run("stops when the next attempt exceeds max elapsed time", func(t *testing.T) {
+		server, bodies := newServer(t, http.Header{"Retry-After": {"120"}}, http.StatusServiceUnavailable)
+		client, sleeps := newClient(t, &ContextConfig{Retry: &RetryConfig{MaxElapsedTime: time.Minute}})
+
+		_, status, err := client.Get(context.Background(), server.URL, "")
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Len(t, *bodies, 1)
+		require.Empty(t, *sleeps)
+	})
+
+	t.Run("retries connection errors", func(t *testing.T) {
+		server, _ := newServer(t, nil)
+		server.Close()
+		client, sleeps := newClient(t, &ContextConfig{})
+
+		_, _, err := client.Get(context.Background(), server.URL, "")
+
+		require.Error(t, err)
+		require.Len(t, *sleeps, DefaultRetryMaxAttempts-1)
+	})
+}
+
+func TestClientCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	t.Run("fails when a request exceeds the timeout", func(t *testing.T) {
+		client, err := NewClient(&ContextConfig{Timeout: 10 * time.Millisecond, Retry: &RetryConfig{MaxAttempts: 1}}, nil)
+		require.NoError(t, err)
+
+		_, _, err = client.Get(context.Background(), server.URL, "")
+
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("stops retrying when the context is cancelled", func(t *testing.T) {
+		client, err := NewClient(&ContextConfig{}, nil)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, _, err = client.Get(ctx, server.URL, "")
+
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("stops getting the auth token when the context is cancelled", func(t *testing.T) {
+		client, err := NewClient(&ContextConfig{}, blockingTokenSource{})
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		_, _, err = client.Get(ctx, server.URL, "")
+
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("stops getting the auth token when the request exceeds the timeout", func(t *testing.T) {
+		client, err := NewClient(&ContextConfig{Timeout: 10 * time.Millisecond}, blockingTokenSource{})
+		require.NoError(t, err)
+
+		_, _, err = client.Get(context.Background(), server.URL, "")
+
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// blockingTokenSource blocks until ctx is done, like a credential helper or
+// an OAuth2 server that does not respond
+type blockingTokenSource struct{}
+
+func (blockingTokenSource) Token(ctx context.Context) (*Token, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 1; attempt < 100; attempt++ {
+		wait := backoff(attempt, 0)
+
+		require.True(t, wait >= retryInitialInterval/2, "attempt %d waits %s", attempt, wait)
+		require.True(t, wait <= retryMaxInterval, "attempt %d waits %s", attempt, wait)
+	}
+}
@@ -0,0 +1,134 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// socketBaseURL is the base URL of the requests sent through unix sockets,
+// the host is only used on the Host header
+const socketBaseURL = "http://localhost"
+
+// ServerAddress is where the requests to the context server are sent
+type ServerAddress struct {
+	// BaseURL prefixes the path of every request
+	BaseURL string
+	// Socket is the unix socket dialed instead of the BaseURL host, empty
+	// for TCP servers
+	Socket string
+}
+
+// ParseServerURL parses the serverUrl of a context. Besides http and https
+// URLs it accepts unix:///path/to.sock and http+unix://%2Fpath%2Fto.sock/prefix,
+// whose socket path is escaped so the URL can have a path prefix.
+func ParseServerURL(serverURL string) (*ServerAddress, error) {
+	switch {
+	case strings.HasPrefix(serverURL, "unix://"):
+		socket := strings.TrimPrefix(serverURL, "unix://")
+		if socket == "" {
+			return nil, fmt.Errorf("bad serverUrl %q: missing socket path", serverURL)
+		}
+		return &ServerAddress{BaseURL: socketBaseURL, Socket: socket}, nil
+	case strings.HasPrefix(serverURL, "http+unix://"):
+		rest := strings.TrimPrefix(serverURL, "http+unix://")
+		escapedSocket, prefix := rest, ""
+		if i := strings.Index(rest, "/"); i >= 0 {
+			escapedSocket, prefix = rest[:i], rest[i:]
+		}
+		socket, err := url.PathUnescape(escapedSocket)
+		if err != nil || socket == "" {
+			return nil, fmt.Errorf("bad serverUrl %q: the socket path must be escaped, e.g. http+unix://%%2Fvar%%2Frun%%2Fmaestro.sock", serverURL)
+		}
+		return &ServerAddress{BaseURL: socketBaseURL + strings.TrimSuffix(prefix, "/"), Socket: socket}, nil
+	}
+
+	u, err := url.ParseRequestURI(serverURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("bad serverUrl %q", serverURL)
+	}
+	return &ServerAddress{BaseURL: strings.TrimSuffix(serverURL, "/")}, nil
+}
+
+// BaseURL returns the URL prefixing the path of the requests, the serverUrl
+// itself for TCP servers
+func (c *ContextConfig) BaseURL() string {
+	address, err := ParseServerURL(c.ServerURL)
+	if err != nil {
+		return c.ServerURL
+	}
+	return address.BaseURL
+}
+
+// ProxyURL returns the proxy of the requests to the context server, nil when
+// they are sent directly
+func (c *ContextConfig) ProxyURL() (*url.URL, error) {
+	if c.Proxy == "" {
+		return nil, nil
+	}
+	req, err := http.NewRequest(http.MethodGet, c.BaseURL(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return proxyFunc(c.Proxy)(req)
+}
+
+// dialSocket returns a dial function connecting to socket whatever the
+// address of the request
+func dialSocket(socket string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{}
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", socket)
+	}
+}
+
+// proxyFunc returns the proxy of the context requests. Like the proxy
+// environment variables, hosts listed by NO_PROXY and localhost are reached
+// directly.
+func proxyFunc(proxy string) func(*http.Request) (*url.URL, error) {
+	config := &httpproxy.Config{
+		HTTPProxy:  proxy,
+		HTTPSProxy: proxy,
+		NoProxy:    getEnvAny("NO_PROXY", "no_proxy"),
+	}
+	proxyURL := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyURL(req.URL)
+	}
+}
+
+func getEnvAny(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// validateProxy checks the proxy is an http, https or socks5 URL
+func validateProxy(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("bad proxy %q", proxy)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return nil
+	}
+	return errors.New("proxy scheme must be http, https or socks5")
+}
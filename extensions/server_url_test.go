@@ -0,0 +1,127 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServerURL(t *testing.T) {
+	testCases := []struct {
+		Title           string
+		ServerURL       string
+		ExpectedAddress *ServerAddress
+		ExpectedError   string
+	}{
+		{
+			Title:           "http url",
+			ServerURL:       "https://maestro.example.com/api/",
+			ExpectedAddress: &ServerAddress{BaseURL: "https://maestro.example.com/api"},
+		}, {
+			Title:           "unix socket",
+			ServerURL:       "unix:///var/run/maestro.sock",
+			ExpectedAddress: &ServerAddress{BaseURL: "http://localhost", Socket: "/var/run/maestro.sock"},
+		}, {
+			Title:           "http over unix socket with a path prefix",
+			ServerURL:       "http+unix://%2Fvar%2Frun%2Fmaestro.sock/api",
+			ExpectedAddress: &ServerAddress{BaseURL: "http://localhost/api", Socket: "/var/run/maestro.sock"},
+		}, {
+			Title:         "unix socket without path",
+			ServerURL:     "unix://",
+			ExpectedError: "bad serverUrl \"unix://\": missing socket path",
+		}, {
+			Title:         "unescaped socket path",
+			ServerURL:     "http+unix:///var/run/maestro.sock",
+			ExpectedError: "bad serverUrl \"http+unix:///var/run/maestro.sock\": the socket path must be escaped, e.g. http+unix://%2Fvar%2Frun%2Fmaestro.sock",
+		}, {
+			Title:         "unknown scheme",
+			ServerURL:     "ftp://maestro.example.com",
+			ExpectedError: "bad serverUrl \"ftp://maestro.example.com\"",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Title, func(t *testing.T) {
+			address, err := ParseServerURL(testCase.ServerURL)
+
+			if testCase.ExpectedError != "" {
+				require.EqualError(t, err, testCase.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.ExpectedAddress, address)
+		})
+	}
+}
+
+func TestClientDialer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends requests through unix sockets", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "maestro")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		socket := filepath.Join(dir, "maestro.sock")
+		listener, err := net.Listen("unix", socket)
+		require.NoError(t, err)
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.URL.Path))
+		}))
+		server.Listener = listener
+		server.Start()
+		defer server.Close()
+
+		config := &ContextConfig{ServerURL: "http+unix://" + url.PathEscape(socket) + "/api"}
+		client, err := NewClient(config, nil)
+		require.NoError(t, err)
+
+		body, status, err := client.Get(ctx, config.BaseURL()+"/schedulers", "")
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "/api/schedulers", string(body))
+	})
+
+	t.Run("sends requests through the proxy", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.URL.String()))
+		}))
+		defer proxy.Close()
+		config := &ContextConfig{ServerURL: "http://maestro.example.com", Proxy: proxy.URL}
+		client, err := NewClient(config, nil)
+		require.NoError(t, err)
+
+		body, _, err := client.Get(ctx, config.BaseURL()+"/schedulers", "")
+
+		require.NoError(t, err)
+		require.Equal(t, "http://maestro.example.com/schedulers", string(body))
+	})
+
+	t.Run("skips the proxy of hosts listed by NO_PROXY", func(t *testing.T) {
+		require.NoError(t, os.Setenv("NO_PROXY", ".example.com"))
+		defer os.Unsetenv("NO_PROXY")
+
+		direct, err := (&ContextConfig{ServerURL: "https://maestro.example.com", Proxy: "socks5://proxy:1080"}).ProxyURL()
+		require.NoError(t, err)
+		require.Nil(t, direct)
+
+		proxied, err := (&ContextConfig{ServerURL: "https://maestro.other.com", Proxy: "socks5://proxy:1080"}).ProxyURL()
+		require.NoError(t, err)
+		require.Equal(t, "socks5://proxy:1080", proxied.String())
+	})
+}
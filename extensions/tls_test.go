@@ -0,0 +1,84 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTLS(t *testing.T) {
+	maestro := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer maestro.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: maestro.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(caFile, caPEM, 0600))
+
+	t.Run("trusts the server with the CA file", func(t *testing.T) {
+		client, err := NewClient(&ContextConfig{CAFile: caFile}, nil)
+		require.NoError(t, err)
+
+		_, status, err := client.Get(context.Background(), maestro.URL, "")
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("fails without the CA file", func(t *testing.T) {
+		client, err := NewClient(&ContextConfig{}, nil)
+		require.NoError(t, err)
+
+		_, _, err = client.Get(context.Background(), maestro.URL, "")
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "certificate")
+	})
+
+	t.Run("skips verification when insecure", func(t *testing.T) {
+		client, err := NewClient(&ContextConfig{InsecureSkipVerify: true}, nil)
+		require.NoError(t, err)
+
+		_, status, err := client.Get(context.Background(), maestro.URL, "")
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("fails when CA file has no certificates", func(t *testing.T) {
+		badFile := filepath.Join(dir, "bad.pem")
+		require.NoError(t, ioutil.WriteFile(badFile, []byte("not a certificate"), 0600))
+
+		_, err := NewClient(&ContextConfig{CAFile: badFile}, nil)
+
+		require.Error(t, err)
+		require.Equal(t, "error parsing CA file "+badFile+": no PEM encoded certificates found", err.Error())
+	})
+
+	t.Run("fails when client certificate has no key", func(t *testing.T) {
+		_, err := NewClient(&ContextConfig{CertFile: caFile}, nil)
+
+		require.Error(t, err)
+		require.Equal(t, "certFile and keyFile must be set together to use a client certificate", err.Error())
+	})
+
+	t.Run("fails when client certificate can not be loaded", func(t *testing.T) {
+		_, err := NewClient(&ContextConfig{CertFile: caFile, KeyFile: caFile}, nil)
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error loading client certificate "+caFile+" with key "+caFile)
+	})
+}
@@ -0,0 +1,208 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redacted replaces secrets in the traces
+const Redacted = "REDACTED"
+
+// redactedHeaders are never traced
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// DefaultRedactFields are the body fields redacted on every context, a field
+// is redacted when its name contains one of them, ignoring case
+var DefaultRedactFields = []string{"password", "secret", "token"}
+
+// TraceConfig configures the traces of a context
+type TraceConfig struct {
+	// RedactFields are redacted besides DefaultRedactFields, e.g. the names
+	// of secret environment variables
+	RedactFields []string `yaml:"redactFields,omitempty"`
+}
+
+// GetRedactFields returns the fields redacted by the context, it is safe to
+// call on nil
+func (t *TraceConfig) GetRedactFields() []string {
+	fields := append([]string{}, DefaultRedactFields...)
+	if t != nil {
+		fields = append(fields, t.RedactFields...)
+	}
+	return fields
+}
+
+// traceTransport writes every request and response it sends to out
+type traceTransport struct {
+	next         http.RoundTripper
+	out          io.Writer
+	curl         bool
+	redactFields []string
+	mu           sync.Mutex
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	var responseBody []byte
+	if err == nil {
+		responseBody, err = ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+	}
+	latency := time.Since(start)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var b strings.Builder
+	fmt.Fprintf(&b, "> %s %s\n", req.Method, req.URL)
+	t.writeHeader(&b, "> ", req.Header)
+	t.writeBody(&b, "> ", requestBody)
+	if t.curl {
+		fmt.Fprintf(&b, "> %s\n", t.curlCommand(req, requestBody))
+	}
+	if err != nil {
+		fmt.Fprintf(&b, "< error after %s: %s\n", latency.Round(time.Millisecond), err)
+	} else {
+		fmt.Fprintf(&b, "< %s (%s)\n", res.Status, latency.Round(time.Millisecond))
+		t.writeHeader(&b, "< ", res.Header)
+		t.writeBody(&b, "< ", responseBody)
+	}
+	fmt.Fprint(t.out, b.String())
+	return res, err
+}
+
+// readRequestBody reads the body without consuming it
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (t *traceTransport) writeHeader(b *strings.Builder, prefix string, header http.Header) {
+	for _, name := range sortedHeaderNames(header) {
+		fmt.Fprintf(b, "%s%s: %s\n", prefix, name, redactHeader(name, header[name]))
+	}
+}
+
+func (t *traceTransport) writeBody(b *strings.Builder, prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s\n%s%s\n", prefix, prefix, RedactBody(body, t.redactFields))
+}
+
+// curlCommand returns a curl command line sending the request
+func (t *traceTransport) curlCommand(req *http.Request, body []byte) string {
+	args := []string{"curl", "-X", req.Method, shellQuote(req.URL.String())}
+	for _, name := range sortedHeaderNames(req.Header) {
+		args = append(args, "-H", shellQuote(name+": "+redactHeader(name, req.Header[name])))
+	}
+	if len(body) > 0 {
+		args = append(args, "--data-raw", shellQuote(string(RedactBody(body, t.redactFields))))
+	}
+	return strings.Join(args, " ")
+}
+
+func sortedHeaderNames(header http.Header) []string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func redactHeader(name string, values []string) string {
+	for _, redacted := range redactedHeaders {
+		if strings.EqualFold(name, redacted) {
+			return Redacted
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// RedactBody replaces the values of the JSON fields whose name contains one
+// of fields, bodies that are not JSON are returned as they are. Environment
+// variables are redacted by name, as they are {"name": ..., "value": ...}.
+// Numbers are kept as they are, so cassettes replay large IDs unchanged.
+func RedactBody(body []byte, fields []string) []byte {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return body
+	}
+	// json.Marshal would escape <, > and &, changing the bodies sent
+	redacted := new(bytes.Buffer)
+	encoder := json.NewEncoder(redacted)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(redactValue(value, fields)); err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(redacted.Bytes(), []byte("\n"))
+}
+
+func redactValue(value interface{}, fields []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if name, ok := v["name"].(string); ok && matchesField(name, fields) {
+			if _, ok := v["value"]; ok {
+				v["value"] = Redacted
+			}
+		}
+		for key, field := range v {
+			if matchesField(key, fields) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redactValue(field, fields)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i], fields)
+		}
+	}
+	return value
+}
+
+func matchesField(name string, fields []string) bool {
+	name = strings.ToLower(name)
+	for _, field := range fields {
+		if field != "" && strings.Contains(name, strings.ToLower(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
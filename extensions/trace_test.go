@@ -0,0 +1,119 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":5,"message":"scheduler not found"}`))
+	}))
+	defer server.Close()
+
+	config := &ContextConfig{Trace: &TraceConfig{RedactFields: []string{"API_KEY"}}}
+	body := `{"name":"scheduler","password":"p4ss","spec":{"containers":[{"environment":[{"name":"API_KEY","value":"k3y"},{"name":"PORT","value":"80"}]}]}}`
+
+	t.Run("writes requests and responses with secrets redacted", func(t *testing.T) {
+		client, err := NewClient(config, &staticTokenSource{token: &Token{AccessToken: "secret-token"}})
+		require.NoError(t, err)
+		var out bytes.Buffer
+		client.EnableTrace(&out, false)
+
+		responseBody, status, err := client.Put(context.Background(), server.URL+"/schedulers/scheduler", body)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, status)
+		require.Equal(t, `{"code":5,"message":"scheduler not found"}`, string(responseBody))
+		trace := out.String()
+		require.Contains(t, trace, "> PUT "+server.URL+"/schedulers/scheduler\n")
+		require.Contains(t, trace, "> Authorization: REDACTED\n")
+		require.Contains(t, trace, "< 404 Not Found (")
+		require.Contains(t, trace, "< Set-Cookie: REDACTED\n")
+		require.Contains(t, trace, `< {"code":5,"message":"scheduler not found"}`)
+		require.Contains(t, trace, `"password":"REDACTED"`)
+		require.Contains(t, trace, `{"name":"API_KEY","value":"REDACTED"}`)
+		require.Contains(t, trace, `{"name":"PORT","value":"80"}`)
+		require.NotContains(t, trace, "secret-token")
+		require.NotContains(t, trace, "p4ss")
+		require.NotContains(t, trace, "k3y")
+		require.NotContains(t, trace, "curl")
+	})
+
+	t.Run("writes the curl command of requests", func(t *testing.T) {
+		client, err := NewClient(config, &staticTokenSource{token: &Token{AccessToken: "secret-token"}})
+		require.NoError(t, err)
+		var out bytes.Buffer
+		client.EnableTrace(&out, true)
+
+		_, _, err = client.Post(context.Background(), server.URL+"/schedulers", `{"name":"it's"}`)
+
+		require.NoError(t, err)
+		require.Contains(t, out.String(), `> curl -X POST '`+server.URL+`/schedulers' -H 'Authorization: REDACTED' --data-raw '{"name":"it'\''s"}'`+"\n")
+	})
+
+	t.Run("writes request errors", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		client, err := NewClient(&ContextConfig{Retry: &RetryConfig{MaxAttempts: 1}}, nil)
+		require.NoError(t, err)
+		var out bytes.Buffer
+		client.EnableTrace(&out, false)
+
+		_, _, err = client.Get(context.Background(), closed.URL, "")
+
+		require.Error(t, err)
+		require.Contains(t, out.String(), "< error after ")
+	})
+}
+
+func TestRedactBody(t *testing.T) {
+	testCases := []struct {
+		Title    string
+		Body     string
+		Fields   []string
+		Expected string
+	}{
+		{
+			Title:    "redacts fields containing the names ignoring case",
+			Body:     `{"clientSecret":"a","AccessToken":"b","game":"c"}`,
+			Fields:   DefaultRedactFields,
+			Expected: `{"AccessToken":"REDACTED","clientSecret":"REDACTED","game":"c"}`,
+		}, {
+			Title:    "redacts nested fields",
+			Body:     `{"items":[{"password":{"value":"a"}}]}`,
+			Fields:   DefaultRedactFields,
+			Expected: `{"items":[{"password":"REDACTED"}]}`,
+		}, {
+			Title:    "keeps html characters unescaped",
+			Body:     `{"command":["./run","--args","a<b&&c>d"],"token":"a"}`,
+			Fields:   DefaultRedactFields,
+			Expected: `{"command":["./run","--args","a<b&&c>d"],"token":"REDACTED"}`,
+		}, {
+			Title:    "keeps bodies that are not json",
+			Body:     `password=a`,
+			Fields:   DefaultRedactFields,
+			Expected: `password=a`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Title, func(t *testing.T) {
+			require.Equal(t, testCase.Expected, string(RedactBody([]byte(testCase.Body), testCase.Fields)))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package extensions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ConfigError is returned when a config file can not be used, Hint tells the
+// user how to fix it
+type ConfigError struct {
+	Path string
+	Err  error
+	Hint string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid config file %s: %s, %s", e.Path, e.Err, e.Hint)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Check validates every context of the config, returning a ConfigError
+// telling how to fix the file
+func (c *Config) Check() error {
+	return newSettingsError(c.Validate())
+}
+
+// CheckContext validates the named context. Commands only check the context
+// they use, so a broken context does not stop the others, e.g. context delete
+// removing it, from working.
+func (c *Config) CheckContext(name string) error {
+	contextConfig, err := c.Context(name)
+	if err != nil {
+		return err
+	}
+	err = contextConfig.Validate()
+	if err != nil {
+		return newSettingsError(fmt.Errorf("context %q: %w", name, err))
+	}
+	return nil
+}
+
+func newSettingsError(err error) error {
+	if err == nil {
+		return nil
+	}
+	configPath, pathErr := getConfigPath()
+	if pathErr != nil {
+		configPath = "config.yaml"
+	}
+	return &ConfigError{Path: configPath, Err: err, Hint: "fix the field or remove the context from the file"}
+}
+
+// Validate checks every context of the config
+func (c *Config) Validate() error {
+	if c.CurrentContext != "" {
+		if _, ok := c.Contexts[c.CurrentContext]; !ok {
+			return fmt.Errorf("currentContext %q does not exist", c.CurrentContext)
+		}
+	}
+	for _, name := range c.ContextNames() {
+		err := c.Contexts[name].Validate()
+		if err != nil {
+			return fmt.Errorf("context %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the settings of a context
+func (c *ContextConfig) Validate() error {
+	if c == nil {
+		return errors.New("context is empty")
+	}
+	if c.ServerURL == "" {
+		return errors.New("serverUrl is required")
+	}
+	address, err := ParseServerURL(c.ServerURL)
+	if err != nil {
+		return err
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("certFile and keyFile must be set together")
+	}
+	if c.Timeout < 0 {
+		return errors.New("timeout must be positive")
+	}
+	if c.Retry != nil && c.Retry.MaxAttempts < 0 {
+		return errors.New("retry.maxAttempts must be positive")
+	}
+	if c.Retry != nil && c.Retry.MaxElapsedTime < 0 {
+		return errors.New("retry.maxElapsedTime must be positive")
+	}
+	if c.Pool != nil && (c.Pool.MaxIdleConns < 0 || c.Pool.MaxIdleConnsPerHost < 0 || c.Pool.IdleConnTimeout < 0) {
+		return errors.New("pool.maxIdleConns, pool.maxIdleConnsPerHost and pool.idleConnTimeout must be positive")
+	}
+	if c.Transport != "" && c.Transport != TransportHTTP && c.Transport != TransportGRPC {
+		return fmt.Errorf("bad transport %q, use one of %s", c.Transport, strings.Join(Transports, ", "))
+	}
+	if c.Proxy != "" {
+		if address.Socket != "" {
+			return errors.New("proxy can not be used with unix socket serverUrl")
+		}
+		if c.GetTransport() == TransportGRPC {
+			return errors.New("proxy needs the http transport")
+		}
+		if err := validateProxy(c.Proxy); err != nil {
+			return err
+		}
+	}
+	err = c.Defaults.validate()
+	if err != nil {
+		return err
+	}
+	return c.Auth.validate()
+}
+
+func (d *Defaults) validate() error {
+	if d == nil {
+		return nil
+	}
+	if d.WaitTimeout < 0 {
+		return errors.New("defaults.waitTimeout must be positive")
+	}
+	if d.Output == "" {
+		return nil
+	}
+	for _, format := range OutputFormats {
+		if d.Output == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("bad defaults.output %q, use one of %s", d.Output, strings.Join(OutputFormats, ", "))
+}
+
+func (a *AuthConfig) validate() error {
+	if a == nil {
+		return nil
+	}
+
+	if a.methods() > 1 {
+		return errors.New("auth must have only one of token, tokenFile, oauth2 or credentialHelper")
+	}
+
+	if a.OAuth2 == nil {
+		return nil
+	}
+	switch a.OAuth2.Flow {
+	case OAuth2ClientCredentials:
+		if a.OAuth2.ClientSecret == "" {
+			return errors.New("auth.oauth2.clientSecret is required by the client-credentials flow")
+		}
+	case OAuth2DeviceCode:
+		if a.OAuth2.DeviceAuthURL == "" {
+			return errors.New("auth.oauth2.deviceAuthUrl is required by the device-code flow")
+		}
+	default:
+		return fmt.Errorf("bad auth.oauth2.flow %q, use %s or %s", a.OAuth2.Flow, OAuth2ClientCredentials, OAuth2DeviceCode)
+	}
+	if a.OAuth2.TokenURL == "" {
+		return errors.New("auth.oauth2.tokenUrl is required")
+	}
+	if a.OAuth2.ClientID == "" {
+		return errors.New("auth.oauth2.clientId is required")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+// Package formats names the output formats, so the config can validate its
+// defaults without depending on the printer
+package formats
+
+const (
+	// Table prints the items as a table, the default
+	Table = "table"
+	// Wide prints the items as a table with more columns
+	Wide = "wide"
+	// JSON prints the response as JSON
+	JSON = "json"
+	// YAML prints the response as YAML
+	YAML = "yaml"
+	// Name prints the name of every item, one per line
+	Name = "name"
+	// CSV prints the items as the table, with comma separated values
+	CSV = "csv"
+	// Markdown prints the items as the table, as a markdown table
+	Markdown = "markdown"
+	// GoTemplate prints the JSON form of the response with the go template
+	// following it, e.g. go-template={{range .schedulers}}{{.name}}{{end}}
+	GoTemplate = "go-template"
+	// GoTemplateFile is GoTemplate reading the template from a file
+	GoTemplateFile = "go-template-file"
+	// JSONPathFormat prints the JSON form of the response with the JSONPath
+	// template following it, e.g. jsonpath={.schedulers[*].name}
+	JSONPathFormat = "jsonpath"
+	// JSONPathFile is JSONPathFormat reading the template from a file
+	JSONPathFile = "jsonpath-file"
+)
+
+// Formats are the values accepted by --output
+var Formats = []string{Table, Wide, JSON, YAML, Name, CSV, Markdown}
+
+// TemplateFormats are the formats of --output followed by =template
+var TemplateFormats = []string{GoTemplate, GoTemplateFile, JSONPathFormat, JSONPathFile}
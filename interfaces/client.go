@@ -7,10 +7,13 @@
 
 package interfaces
 
+import "context"
+
 //Client interface
 type Client interface {
-	Get(url, body string) ([]byte, int, error)
-	Put(url, body string) ([]byte, int, error)
-	Post(url, body string) ([]byte, int, error)
-	Delete(url string) ([]byte, int, error)
+	Get(ctx context.Context, url, body string) ([]byte, int, error)
+	Put(ctx context.Context, url, body string) ([]byte, int, error)
+	Post(ctx context.Context, url, body string) ([]byte, int, error)
+	IdempotentPost(ctx context.Context, url, body, idempotencyKey string) ([]byte, int, error)
+	Delete(ctx context.Context, url string) ([]byte, int, error)
 }
@@ -17,6 +17,10 @@ import (
 type FileSystem interface {
 	MkdirAll(path string, perm os.FileMode) error
 	Create(name string) (afero.File, error)
+	OpenFile(name string, flag int, perm os.FileMode) (afero.File, error)
 	IsNotExist(err error) bool
 	Stat(name string) (os.FileInfo, error)
+	ReadFile(name string) ([]byte, error)
+	Glob(pattern string) ([]string, error)
+	Rename(oldpath, newpath string) error
 }
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -34,9 +35,9 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 }
 
 // Get mocks base method
-func (m *MockClient) Get(url, body string) ([]byte, int, error) {
+func (m *MockClient) Get(ctx context.Context, url, body string) ([]byte, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", url, body)
+	ret := m.ctrl.Call(m, "Get", ctx, url, body)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
@@ -44,15 +45,15 @@ func (m *MockClient) Get(url, body string) ([]byte, int, error) {
 }
 
 // Get indicates an expected call of Get
-func (mr *MockClientMockRecorder) Get(url, body interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) Get(ctx, url, body interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), url, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClient)(nil).Get), ctx, url, body)
 }
 
 // Put mocks base method
-func (m *MockClient) Put(url, body string) ([]byte, int, error) {
+func (m *MockClient) Put(ctx context.Context, url, body string) ([]byte, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Put", url, body)
+	ret := m.ctrl.Call(m, "Put", ctx, url, body)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
@@ -60,15 +61,15 @@ func (m *MockClient) Put(url, body string) ([]byte, int, error) {
 }
 
 // Put indicates an expected call of Put
-func (mr *MockClientMockRecorder) Put(url, body interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) Put(ctx, url, body interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockClient)(nil).Put), url, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockClient)(nil).Put), ctx, url, body)
 }
 
 // Post mocks base method
-func (m *MockClient) Post(url, body string) ([]byte, int, error) {
+func (m *MockClient) Post(ctx context.Context, url, body string) ([]byte, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Post", url, body)
+	ret := m.ctrl.Call(m, "Post", ctx, url, body)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
@@ -76,15 +77,31 @@ func (m *MockClient) Post(url, body string) ([]byte, int, error) {
 }
 
 // Post indicates an expected call of Post
-func (mr *MockClientMockRecorder) Post(url, body interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) Post(ctx, url, body interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockClient)(nil).Post), url, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockClient)(nil).Post), ctx, url, body)
+}
+
+// IdempotentPost mocks base method
+func (m *MockClient) IdempotentPost(ctx context.Context, url, body, idempotencyKey string) ([]byte, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IdempotentPost", ctx, url, body, idempotencyKey)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// IdempotentPost indicates an expected call of IdempotentPost
+func (mr *MockClientMockRecorder) IdempotentPost(ctx, url, body, idempotencyKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IdempotentPost", reflect.TypeOf((*MockClient)(nil).IdempotentPost), ctx, url, body, idempotencyKey)
 }
 
 // Delete mocks base method
-func (m *MockClient) Delete(url string) ([]byte, int, error) {
+func (m *MockClient) Delete(ctx context.Context, url string) ([]byte, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", url)
+	ret := m.ctrl.Call(m, "Delete", ctx, url)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
@@ -92,7 +109,7 @@ func (m *MockClient) Delete(url string) ([]byte, int, error) {
 }
 
 // Delete indicates an expected call of Delete
-func (mr *MockClientMockRecorder) Delete(url interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) Delete(ctx, url interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), url)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), ctx, url)
 }
@@ -0,0 +1,258 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+// Package maestro is a typed client of the maestro v1 API
+package maestro
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/topfreegames/maestro-cli/interfaces"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Client calls every endpoint of the maestro v1 API
+type Client interface {
+	ListSchedulers(ctx context.Context, request *v1.ListSchedulersRequest) (*v1.ListSchedulersResponse, error)
+	GetScheduler(ctx context.Context, request *v1.GetSchedulerRequest) (*v1.GetSchedulerResponse, error)
+	CreateScheduler(ctx context.Context, request *v1.CreateSchedulerRequest) (*v1.CreateSchedulerResponse, error)
+	NewSchedulerVersion(ctx context.Context, request *v1.NewSchedulerVersionRequest) (*v1.NewSchedulerVersionResponse, error)
+	SwitchActiveVersion(ctx context.Context, request *v1.SwitchActiveVersionRequest) (*v1.SwitchActiveVersionResponse, error)
+	GetSchedulerVersions(ctx context.Context, request *v1.GetSchedulerVersionsRequest) (*v1.GetSchedulerVersionsResponse, error)
+	GetSchedulersInfo(ctx context.Context, request *v1.GetSchedulersInfoRequest) (*v1.GetSchedulersInfoResponse, error)
+	AddRooms(ctx context.Context, request *v1.AddRoomsRequest) (*v1.AddRoomsResponse, error)
+	RemoveRooms(ctx context.Context, request *v1.RemoveRoomsRequest) (*v1.RemoveRoomsResponse, error)
+	ListOperations(ctx context.Context, request *v1.ListOperationsRequest) (*v1.ListOperationsResponse, error)
+	GetOperation(ctx context.Context, request *v1.GetOperationRequest) (*v1.GetOperationResponse, error)
+	CancelOperation(ctx context.Context, request *v1.CancelOperationRequest) (*v1.CancelOperationResponse, error)
+	UpdateRoomWithPing(ctx context.Context, request *v1.UpdateRoomWithPingRequest) (*v1.UpdateRoomWithPingResponse, error)
+	ForwardRoomEvent(ctx context.Context, request *v1.ForwardRoomEventRequest) (*v1.ForwardRoomEventResponse, error)
+	ForwardPlayerEvent(ctx context.Context, request *v1.ForwardPlayerEventRequest) (*v1.ForwardPlayerEventResponse, error)
+	UpdateRoomStatus(ctx context.Context, request *v1.UpdateRoomStatusRequest) (*v1.UpdateRoomStatusResponse, error)
+}
+
+// HTTPClient calls the maestro v1 API through its HTTP gateway
+type HTTPClient struct {
+	client    interfaces.Client
+	serverURL string
+}
+
+// NewClient ctor, requests are sent by client to serverURL
+func NewClient(client interfaces.Client, serverURL string) *HTTPClient {
+	return &HTTPClient{
+		client:    client,
+		serverURL: strings.TrimSuffix(serverURL, "/"),
+	}
+}
+
+// ListSchedulers lists the schedulers matching the request filters
+func (c *HTTPClient) ListSchedulers(ctx context.Context, request *v1.ListSchedulersRequest) (*v1.ListSchedulersResponse, error) {
+	query := url.Values{}
+	addQuery(query, "name", request.GetName())
+	addQuery(query, "game", request.GetGame())
+	addQuery(query, "version", request.GetVersion())
+
+	response := &v1.ListSchedulersResponse{}
+	err := c.do(ctx, "get schedulers", http.MethodGet, path(query, "schedulers"), nil, response)
+	return response, err
+}
+
+// GetScheduler gets a scheduler, in its active version unless the request
+// sets one
+func (c *HTTPClient) GetScheduler(ctx context.Context, request *v1.GetSchedulerRequest) (*v1.GetSchedulerResponse, error) {
+	query := url.Values{}
+	addQuery(query, "version", request.GetVersion())
+
+	response := &v1.GetSchedulerResponse{}
+	err := c.do(ctx, "get scheduler", http.MethodGet, path(query, "schedulers", request.GetSchedulerName()), nil, response)
+	return response, err
+}
+
+// CreateScheduler creates a scheduler
+func (c *HTTPClient) CreateScheduler(ctx context.Context, request *v1.CreateSchedulerRequest) (*v1.CreateSchedulerResponse, error) {
+	response := &v1.CreateSchedulerResponse{}
+	err := c.do(ctx, "create scheduler", http.MethodPost, path(nil, "schedulers"), request, response)
+	return response, err
+}
+
+// NewSchedulerVersion enqueues the operation creating a scheduler version
+func (c *HTTPClient) NewSchedulerVersion(ctx context.Context, request *v1.NewSchedulerVersionRequest) (*v1.NewSchedulerVersionResponse, error) {
+	response := &v1.NewSchedulerVersionResponse{}
+	err := c.do(ctx, "new scheduler version", http.MethodPost, path(nil, "schedulers", request.GetName()), request, response)
+	return response, err
+}
+
+// SwitchActiveVersion enqueues the operation switching the scheduler active
+// version
+func (c *HTTPClient) SwitchActiveVersion(ctx context.Context, request *v1.SwitchActiveVersionRequest) (*v1.SwitchActiveVersionResponse, error) {
+	body := &v1.SwitchActiveVersionRequest{Version: request.GetVersion()}
+	response := &v1.SwitchActiveVersionResponse{}
+	err := c.do(ctx, "switch active version", http.MethodPut, path(nil, "schedulers", request.GetSchedulerName()), body, response)
+	return response, err
+}
+
+// GetSchedulerVersions lists the versions of a scheduler
+func (c *HTTPClient) GetSchedulerVersions(ctx context.Context, request *v1.GetSchedulerVersionsRequest) (*v1.GetSchedulerVersionsResponse, error) {
+	response := &v1.GetSchedulerVersionsResponse{}
+	err := c.do(ctx, "get scheduler versions", http.MethodGet, path(nil, "schedulers", request.GetSchedulerName(), "versions"), nil, response)
+	return response, err
+}
+
+// GetSchedulersInfo gets the schedulers and game rooms information, of every
+// game unless the request sets one
+func (c *HTTPClient) GetSchedulersInfo(ctx context.Context, request *v1.GetSchedulersInfoRequest) (*v1.GetSchedulersInfoResponse, error) {
+	query := url.Values{}
+	addQuery(query, "game", request.GetGame())
+
+	response := &v1.GetSchedulersInfoResponse{}
+	err := c.do(ctx, "get schedulers info", http.MethodGet, path(query, "schedulers", "info"), nil, response)
+	return response, err
+}
+
+// AddRooms enqueues the operation adding rooms to a scheduler. The request is
+// sent with an idempotency key so it is retried on transient failures.
+func (c *HTTPClient) AddRooms(ctx context.Context, request *v1.AddRoomsRequest) (*v1.AddRoomsResponse, error) {
+	body := &v1.AddRoomsRequest{Amount: request.GetAmount()}
+	response := &v1.AddRoomsResponse{}
+	err := c.do(ctx, "add rooms", http.MethodPost, path(nil, "schedulers", request.GetSchedulerName(), "add-rooms"), body, response, uuid.New().String())
+	return response, err
+}
+
+// RemoveRooms enqueues the operation removing rooms from a scheduler
+func (c *HTTPClient) RemoveRooms(ctx context.Context, request *v1.RemoveRoomsRequest) (*v1.RemoveRoomsResponse, error) {
+	body := &v1.RemoveRoomsRequest{Amount: request.GetAmount()}
+	response := &v1.RemoveRoomsResponse{}
+	err := c.do(ctx, "remove rooms", http.MethodPost, path(nil, "schedulers", request.GetSchedulerName(), "remove-rooms"), body, response)
+	return response, err
+}
+
+// ListOperations lists the pending, active and finished operations of a
+// scheduler
+func (c *HTTPClient) ListOperations(ctx context.Context, request *v1.ListOperationsRequest) (*v1.ListOperationsResponse, error) {
+	query := url.Values{}
+	addQuery(query, "order_by", request.GetOrderBy())
+
+	response := &v1.ListOperationsResponse{}
+	err := c.do(ctx, "get operations", http.MethodGet, path(query, "schedulers", request.GetSchedulerName(), "operations"), nil, response)
+	return response, err
+}
+
+// GetOperation gets an operation of a scheduler
+func (c *HTTPClient) GetOperation(ctx context.Context, request *v1.GetOperationRequest) (*v1.GetOperationResponse, error) {
+	response := &v1.GetOperationResponse{}
+	err := c.do(ctx, "get operation", http.MethodGet, path(nil, "schedulers", request.GetSchedulerName(), "operations", request.GetOperationId()), nil, response)
+	return response, err
+}
+
+// CancelOperation cancels a pending or active operation of a scheduler
+func (c *HTTPClient) CancelOperation(ctx context.Context, request *v1.CancelOperationRequest) (*v1.CancelOperationResponse, error) {
+	response := &v1.CancelOperationResponse{}
+	err := c.do(ctx, "cancel operation", http.MethodPost, path(nil, "schedulers", request.GetSchedulerName(), "operations", request.GetOperationId(), "cancel"), &v1.CancelOperationRequest{}, response)
+	return response, err
+}
+
+// UpdateRoomWithPing updates a room status, like the game room pings do
+func (c *HTTPClient) UpdateRoomWithPing(ctx context.Context, request *v1.UpdateRoomWithPingRequest) (*v1.UpdateRoomWithPingResponse, error) {
+	body := &v1.UpdateRoomWithPingRequest{Metadata: request.GetMetadata(), Status: request.GetStatus(), Timestamp: request.GetTimestamp()}
+	response := &v1.UpdateRoomWithPingResponse{}
+	err := c.do(ctx, "update room with ping", http.MethodPut, path(nil, "scheduler", request.GetSchedulerName(), "rooms", request.GetRoomName(), "ping"), body, response)
+	return response, err
+}
+
+// ForwardRoomEvent forwards a room event to the scheduler forwarders
+func (c *HTTPClient) ForwardRoomEvent(ctx context.Context, request *v1.ForwardRoomEventRequest) (*v1.ForwardRoomEventResponse, error) {
+	body := &v1.ForwardRoomEventRequest{Metadata: request.GetMetadata(), Event: request.GetEvent(), Timestamp: request.GetTimestamp()}
+	response := &v1.ForwardRoomEventResponse{}
+	err := c.do(ctx, "forward room event", http.MethodPost, path(nil, "scheduler", request.GetSchedulerName(), "rooms", request.GetRoomName(), "roomevent"), body, response)
+	return response, err
+}
+
+// ForwardPlayerEvent forwards a player event to the scheduler forwarders
+func (c *HTTPClient) ForwardPlayerEvent(ctx context.Context, request *v1.ForwardPlayerEventRequest) (*v1.ForwardPlayerEventResponse, error) {
+	body := &v1.ForwardPlayerEventRequest{Metadata: request.GetMetadata(), Event: request.GetEvent(), Timestamp: request.GetTimestamp()}
+	response := &v1.ForwardPlayerEventResponse{}
+	err := c.do(ctx, "forward player event", http.MethodPost, path(nil, "scheduler", request.GetSchedulerName(), "rooms", request.GetRoomName(), "playerevent"), body, response)
+	return response, err
+}
+
+// UpdateRoomStatus updates a room status
+func (c *HTTPClient) UpdateRoomStatus(ctx context.Context, request *v1.UpdateRoomStatusRequest) (*v1.UpdateRoomStatusResponse, error) {
+	body := &v1.UpdateRoomStatusRequest{Metadata: request.GetMetadata(), Status: request.GetStatus(), Timestamp: request.GetTimestamp()}
+	response := &v1.UpdateRoomStatusResponse{}
+	err := c.do(ctx, "update room status", http.MethodPut, path(nil, "scheduler", request.GetSchedulerName(), "rooms", request.GetRoomName(), "status"), body, response)
+	return response, err
+}
+
+// path joins the escaped segments and the query
+func path(query url.Values, segments ...string) string {
+	escaped := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	p := "/" + strings.Join(escaped, "/")
+	if len(query) > 0 {
+		p += "?" + query.Encode()
+	}
+	return p
+}
+
+func addQuery(query url.Values, name, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+// do sends the request, decoding the response or the error. POST requests are
+// only retried when an idempotency key is given.
+func (c *HTTPClient) do(ctx context.Context, operation, method, path string, request, response proto.Message, idempotencyKey ...string) error {
+	var body string
+	if request != nil {
+		serializedRequest, err := protojson.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("error parsing request to json: %w", err)
+		}
+		body = string(serializedRequest)
+	}
+
+	url := c.serverURL + path
+	var responseBody []byte
+	var status int
+	var err error
+	switch {
+	case method == http.MethodGet:
+		responseBody, status, err = c.client.Get(ctx, url, body)
+	case method == http.MethodPut:
+		responseBody, status, err = c.client.Put(ctx, url, body)
+	case method == http.MethodPost && len(idempotencyKey) > 0:
+		responseBody, status, err = c.client.IdempotentPost(ctx, url, body, idempotencyKey[0])
+	case method == http.MethodPost:
+		responseBody, status, err = c.client.Post(ctx, url, body)
+	case method == http.MethodDelete:
+		responseBody, status, err = c.client.Delete(ctx, url)
+	default:
+		return fmt.Errorf("unsupported method %s", method)
+	}
+	if err != nil {
+		return fmt.Errorf("error on %s request: %w", method, err)
+	}
+	if status < 200 || status > 299 {
+		return NewAPIError(operation, status, responseBody)
+	}
+
+	err = Decode(responseBody, response)
+	if err != nil {
+		return fmt.Errorf("error parsing response body of %s: %w", operation, err)
+	}
+	return nil
+}
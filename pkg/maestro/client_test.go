@@ -0,0 +1,194 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package maestro
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/topfreegames/maestro-cli/mocks"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+)
+
+const serverURL = "http://localhost:8080"
+
+func TestClientRequests(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("escapes path segments", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(ctx, serverURL+"/schedulers/some%20scheduler%2Fname?version=v1.0.0", "").
+			Return([]byte(`{"scheduler":{"name":"some scheduler/name"}}`), http.StatusOK, nil)
+
+		response, err := NewClient(client, serverURL+"/").GetScheduler(ctx, &v1.GetSchedulerRequest{SchedulerName: "some scheduler/name", Version: "v1.0.0"})
+
+		require.NoError(t, err)
+		require.Equal(t, "some scheduler/name", response.GetScheduler().GetName())
+	})
+
+	t.Run("sends path fields in the path only", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Post(ctx, serverURL+"/schedulers/scheduler/remove-rooms", `{"amount":10}`).
+			Return([]byte(`{"operationId":"abc"}`), http.StatusOK, nil)
+
+		response, err := NewClient(client, serverURL).RemoveRooms(ctx, &v1.RemoveRoomsRequest{SchedulerName: "scheduler", Amount: 10})
+
+		require.NoError(t, err)
+		require.Equal(t, "abc", response.GetOperationId())
+	})
+
+	t.Run("sends add rooms with an idempotency key", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().IdempotentPost(ctx, serverURL+"/schedulers/scheduler/add-rooms", `{"amount":10}`, gomock.Not("")).
+			Return([]byte(`{}`), http.StatusOK, nil)
+
+		_, err := NewClient(client, serverURL).AddRooms(ctx, &v1.AddRoomsRequest{SchedulerName: "scheduler", Amount: 10})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("sends room updates to the scheduler rooms", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		var body string
+		client.EXPECT().Put(ctx, serverURL+"/scheduler/scheduler/rooms/room/status", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, requestBody string) ([]byte, int, error) {
+				body = requestBody
+				return []byte(`{"success":true}`), http.StatusOK, nil
+			})
+
+		response, err := NewClient(client, serverURL).UpdateRoomStatus(ctx, &v1.UpdateRoomStatusRequest{SchedulerName: "scheduler", RoomName: "room", Status: "ready", Timestamp: 10})
+
+		require.NoError(t, err)
+		require.True(t, response.GetSuccess())
+		require.JSONEq(t, `{"status":"ready","timestamp":"10"}`, body)
+	})
+
+	t.Run("sends the schedulers info game as a query value", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(ctx, serverURL+"/schedulers/info?game=some-game", "").
+			Return([]byte(`{}`), http.StatusOK, nil)
+
+		_, err := NewClient(client, serverURL).GetSchedulersInfo(ctx, &v1.GetSchedulersInfoRequest{Game: "some-game"})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("adds the list operations order", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(ctx, serverURL+"/schedulers/scheduler/operations?order_by=desc", "").
+			Return([]byte(`{}`), http.StatusOK, nil)
+
+		_, err := NewClient(client, serverURL).ListOperations(ctx, &v1.ListOperationsRequest{SchedulerName: "scheduler", OrderBy: "desc"})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestListSchedulersQuery(t *testing.T) {
+	testCases := []struct {
+		Title         string
+		Request       *v1.ListSchedulersRequest
+		ExpectedQuery string
+	}{
+		{
+			Title:         "when there are no filters",
+			Request:       &v1.ListSchedulersRequest{},
+			ExpectedQuery: "",
+		}, {
+			Title:         "when there is only name",
+			Request:       &v1.ListSchedulersRequest{Name: "some-name"},
+			ExpectedQuery: "?name=some-name",
+		}, {
+			Title:         "when there is only game",
+			Request:       &v1.ListSchedulersRequest{Game: "some-game"},
+			ExpectedQuery: "?game=some-game",
+		}, {
+			Title:         "when there is only version",
+			Request:       &v1.ListSchedulersRequest{Version: "some-version"},
+			ExpectedQuery: "?version=some-version",
+		}, {
+			Title:         "when there are name and game",
+			Request:       &v1.ListSchedulersRequest{Name: "some-name", Game: "some-game"},
+			ExpectedQuery: "?game=some-game&name=some-name",
+		}, {
+			Title:         "when there are all filters",
+			Request:       &v1.ListSchedulersRequest{Name: "some-name", Game: "some-game", Version: "some-version"},
+			ExpectedQuery: "?game=some-game&name=some-name&version=some-version",
+		}, {
+			Title:         "when filters have reserved characters",
+			Request:       &v1.ListSchedulersRequest{Name: "some name&game=other"},
+			ExpectedQuery: "?name=some+name%26game%3Dother",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Title, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			client := mocks.NewMockClient(mockCtrl)
+			client.EXPECT().Get(gomock.Any(), serverURL+"/schedulers"+testCase.ExpectedQuery, "").
+				Return([]byte(`{}`), http.StatusOK, nil)
+
+			_, err := NewClient(client, serverURL).ListSchedulers(context.Background(), testCase.Request)
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	ctx := context.Background()
+	request := &v1.GetOperationRequest{SchedulerName: "scheduler", OperationId: "abc"}
+	url := serverURL + "/schedulers/scheduler/operations/abc"
+
+	t.Run("fails when the request fails", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		clientErr := errors.New("connection refused")
+		client.EXPECT().Get(ctx, url, "").Return(nil, 0, clientErr)
+
+		_, err := NewClient(client, serverURL).GetOperation(ctx, request)
+
+		require.ErrorIs(t, err, clientErr)
+		require.EqualError(t, err, "error on GET request: connection refused")
+	})
+
+	t.Run("fails with an api error when maestro responds not ok", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(ctx, url, "").Return([]byte(`{"code":5,"message":"operation abc not found","details":[]}`), http.StatusNotFound, nil)
+
+		_, err := NewClient(client, serverURL).GetOperation(ctx, request)
+
+		var apiErr *APIError
+		require.True(t, errors.As(err, &apiErr))
+		require.Equal(t, codes.NotFound, apiErr.Code)
+		require.EqualError(t, err, "get operation failed: operation abc not found")
+	})
+
+	t.Run("fails when the response does not match the API", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		client := mocks.NewMockClient(mockCtrl)
+		client.EXPECT().Get(ctx, url, "").Return([]byte(`not json`), http.StatusOK, nil)
+
+		_, err := NewClient(client, serverURL).GetOperation(ctx, request)
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error parsing response body of get operation")
+	})
+}
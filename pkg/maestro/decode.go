@@ -0,0 +1,51 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package maestro
+
+import (
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// StrictDecoding makes Decode fail on fields unknown to the API messages, the
+// commands set it from the MAESTRO_STRICT_DECODING env
+var StrictDecoding bool
+
+var (
+	unknownFieldsHandler func(message string, err error)
+	unknownFieldsOnce    sync.Once
+)
+
+// OnUnknownFields sets the function called with the first response that has
+// fields unknown to the API messages, once per process. It must be set
+// before any request.
+func OnUnknownFields(handler func(message string, err error)) {
+	unknownFieldsHandler = handler
+}
+
+// Decode unmarshals a response into message. Fields unknown to message are
+// discarded, so responses of newer maestro servers are still decoded.
+func Decode(body []byte, message proto.Message) error {
+	err := protojson.Unmarshal(body, message)
+	if err == nil || StrictDecoding {
+		return err
+	}
+
+	tolerantErr := protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, message)
+	if tolerantErr != nil {
+		return tolerantErr
+	}
+	if unknownFieldsHandler != nil {
+		unknownFieldsOnce.Do(func() {
+			unknownFieldsHandler(string(message.ProtoReflect().Descriptor().FullName()), err)
+		})
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package maestro
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+)
+
+func TestDecode(t *testing.T) {
+	body := []byte(`{"schedulers":[{"name":"scheduler","newField":"value"}],"newField":1}`)
+
+	t.Run("discards unknown fields and calls the handler once", func(t *testing.T) {
+		defer func() {
+			unknownFieldsHandler = nil
+			unknownFieldsOnce = sync.Once{}
+		}()
+		var messages []string
+		OnUnknownFields(func(message string, err error) {
+			require.Error(t, err)
+			messages = append(messages, message)
+		})
+
+		for i := 0; i < 2; i++ {
+			response := &v1.ListSchedulersResponse{}
+			require.NoError(t, Decode(body, response))
+			require.Len(t, response.Schedulers, 1)
+			require.Equal(t, "scheduler", response.Schedulers[0].Name)
+		}
+
+		require.Equal(t, []string{"api.v1.ListSchedulersResponse"}, messages)
+	})
+
+	t.Run("fails on unknown fields when strict", func(t *testing.T) {
+		StrictDecoding = true
+		defer func() { StrictDecoding = false }()
+
+		err := Decode(body, &v1.ListSchedulersResponse{})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "newField")
+	})
+
+	t.Run("fails when fields do not match", func(t *testing.T) {
+		err := Decode([]byte(`{"schedulers":"scheduler"}`), &v1.ListSchedulersResponse{})
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,94 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package maestro
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// APIError is returned when maestro responds with a non 2xx status, it holds
+// the error envelope of the grpc-gateway
+type APIError struct {
+	Operation  string
+	StatusCode int
+	Code       codes.Code
+	Message    string
+	Details    []json.RawMessage
+	// Body is the raw response, kept when it is not an error envelope
+	Body []byte
+}
+
+// envelope is the body of the grpc-gateway error responses
+type envelope struct {
+	Code    *int              `json:"code"`
+	Message string            `json:"message"`
+	Details []json.RawMessage `json:"details"`
+}
+
+// NewAPIError parses the error envelope of the response, the code is taken
+// from the HTTP status when the body is not an envelope
+func NewAPIError(operation string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		Operation:  operation,
+		StatusCode: statusCode,
+		Code:       codeFromStatus(statusCode),
+		Body:       body,
+	}
+
+	var e envelope
+	if err := json.Unmarshal(body, &e); err == nil && e.Code != nil {
+		apiErr.Code = codes.Code(*e.Code)
+		apiErr.Message = e.Message
+		apiErr.Details = e.Details
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s failed: %s", e.Operation, e.Message)
+	}
+	message := fmt.Sprintf("%s failed with status %s", e.Operation, http.StatusText(e.StatusCode))
+	if body := strings.TrimSpace(string(e.Body)); body != "" {
+		message += ": " + body
+	}
+	return message
+}
+
+// codeFromStatus reverses the status mapping of the grpc-gateway
+func codeFromStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unknown
+	}
+}
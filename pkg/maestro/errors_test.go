@@ -0,0 +1,69 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package maestro
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestNewAPIError(t *testing.T) {
+	testCases := []struct {
+		Title           string
+		StatusCode      int
+		Body            string
+		ExpectedCode    codes.Code
+		ExpectedMessage string
+		ExpectedDetails int
+	}{
+		{
+			Title:           "parses the grpc-gateway envelope",
+			StatusCode:      http.StatusConflict,
+			Body:            `{"code":6,"message":"scheduler scheduler-name already exists","details":[{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"EXISTS"}]}`,
+			ExpectedCode:    codes.AlreadyExists,
+			ExpectedMessage: "create scheduler failed: scheduler scheduler-name already exists",
+			ExpectedDetails: 1,
+		}, {
+			Title:           "prefers the envelope code to the status",
+			StatusCode:      http.StatusBadRequest,
+			Body:            `{"code":9,"message":"scheduler has an operation running"}`,
+			ExpectedCode:    codes.FailedPrecondition,
+			ExpectedMessage: "create scheduler failed: scheduler has an operation running",
+		}, {
+			Title:           "takes the code from the status without an envelope",
+			StatusCode:      http.StatusUnauthorized,
+			Body:            "<html>unauthorized</html>\n",
+			ExpectedCode:    codes.Unauthenticated,
+			ExpectedMessage: "create scheduler failed with status Unauthorized: <html>unauthorized</html>",
+		}, {
+			Title:           "takes the code from the status with an empty body",
+			StatusCode:      http.StatusServiceUnavailable,
+			ExpectedCode:    codes.Unavailable,
+			ExpectedMessage: "create scheduler failed with status Service Unavailable",
+		}, {
+			Title:           "uses unknown for other statuses",
+			StatusCode:      http.StatusTeapot,
+			Body:            "{}",
+			ExpectedCode:    codes.Unknown,
+			ExpectedMessage: "create scheduler failed with status I'm a teapot: {}",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Title, func(t *testing.T) {
+			err := NewAPIError("create scheduler", testCase.StatusCode, []byte(testCase.Body))
+
+			require.Equal(t, testCase.ExpectedCode, err.Code)
+			require.Equal(t, testCase.ExpectedMessage, err.Error())
+			require.Len(t, err.Details, testCase.ExpectedDetails)
+		})
+	}
+}
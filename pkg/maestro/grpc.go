@@ -0,0 +1,207 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package maestro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// GRPCClient calls the maestro v1 gRPC services directly
+type GRPCClient struct {
+	schedulers v1.SchedulersServiceClient
+	operations v1.OperationsServiceClient
+	rooms      v1.RoomsServiceClient
+}
+
+// NewGRPCClient ctor, calls are sent through conn
+func NewGRPCClient(conn grpc.ClientConnInterface) *GRPCClient {
+	return &GRPCClient{
+		schedulers: v1.NewSchedulersServiceClient(conn),
+		operations: v1.NewOperationsServiceClient(conn),
+		rooms:      v1.NewRoomsServiceClient(conn),
+	}
+}
+
+// ListSchedulers lists the schedulers matching the request filters
+func (c *GRPCClient) ListSchedulers(ctx context.Context, request *v1.ListSchedulersRequest) (*v1.ListSchedulersResponse, error) {
+	response, err := c.schedulers.ListSchedulers(ctx, request)
+	if err != nil {
+		return nil, grpcError("get schedulers", err)
+	}
+	return response, nil
+}
+
+// GetScheduler gets a scheduler, in its active version unless the request
+// sets one
+func (c *GRPCClient) GetScheduler(ctx context.Context, request *v1.GetSchedulerRequest) (*v1.GetSchedulerResponse, error) {
+	response, err := c.schedulers.GetScheduler(ctx, request)
+	if err != nil {
+		return nil, grpcError("get scheduler", err)
+	}
+	return response, nil
+}
+
+// CreateScheduler creates a scheduler
+func (c *GRPCClient) CreateScheduler(ctx context.Context, request *v1.CreateSchedulerRequest) (*v1.CreateSchedulerResponse, error) {
+	response, err := c.schedulers.CreateScheduler(ctx, request)
+	if err != nil {
+		return nil, grpcError("create scheduler", err)
+	}
+	return response, nil
+}
+
+// NewSchedulerVersion enqueues the operation creating a scheduler version
+func (c *GRPCClient) NewSchedulerVersion(ctx context.Context, request *v1.NewSchedulerVersionRequest) (*v1.NewSchedulerVersionResponse, error) {
+	response, err := c.schedulers.NewSchedulerVersion(ctx, request)
+	if err != nil {
+		return nil, grpcError("new scheduler version", err)
+	}
+	return response, nil
+}
+
+// SwitchActiveVersion enqueues the operation switching the scheduler active
+// version
+func (c *GRPCClient) SwitchActiveVersion(ctx context.Context, request *v1.SwitchActiveVersionRequest) (*v1.SwitchActiveVersionResponse, error) {
+	response, err := c.schedulers.SwitchActiveVersion(ctx, request)
+	if err != nil {
+		return nil, grpcError("switch active version", err)
+	}
+	return response, nil
+}
+
+// GetSchedulerVersions lists the versions of a scheduler
+func (c *GRPCClient) GetSchedulerVersions(ctx context.Context, request *v1.GetSchedulerVersionsRequest) (*v1.GetSchedulerVersionsResponse, error) {
+	response, err := c.schedulers.GetSchedulerVersions(ctx, request)
+	if err != nil {
+		return nil, grpcError("get scheduler versions", err)
+	}
+	return response, nil
+}
+
+// GetSchedulersInfo gets the schedulers and game rooms information, of every
+// game unless the request sets one
+func (c *GRPCClient) GetSchedulersInfo(ctx context.Context, request *v1.GetSchedulersInfoRequest) (*v1.GetSchedulersInfoResponse, error) {
+	response, err := c.schedulers.GetSchedulersInfo(ctx, request)
+	if err != nil {
+		return nil, grpcError("get schedulers info", err)
+	}
+	return response, nil
+}
+
+// AddRooms enqueues the operation adding rooms to a scheduler
+func (c *GRPCClient) AddRooms(ctx context.Context, request *v1.AddRoomsRequest) (*v1.AddRoomsResponse, error) {
+	response, err := c.schedulers.AddRooms(ctx, request)
+	if err != nil {
+		return nil, grpcError("add rooms", err)
+	}
+	return response, nil
+}
+
+// RemoveRooms enqueues the operation removing rooms from a scheduler
+func (c *GRPCClient) RemoveRooms(ctx context.Context, request *v1.RemoveRoomsRequest) (*v1.RemoveRoomsResponse, error) {
+	response, err := c.schedulers.RemoveRooms(ctx, request)
+	if err != nil {
+		return nil, grpcError("remove rooms", err)
+	}
+	return response, nil
+}
+
+// ListOperations lists the pending, active and finished operations of a
+// scheduler
+func (c *GRPCClient) ListOperations(ctx context.Context, request *v1.ListOperationsRequest) (*v1.ListOperationsResponse, error) {
+	response, err := c.operations.ListOperations(ctx, request)
+	if err != nil {
+		return nil, grpcError("get operations", err)
+	}
+	return response, nil
+}
+
+// GetOperation gets an operation of a scheduler
+func (c *GRPCClient) GetOperation(ctx context.Context, request *v1.GetOperationRequest) (*v1.GetOperationResponse, error) {
+	response, err := c.operations.GetOperation(ctx, request)
+	if err != nil {
+		return nil, grpcError("get operation", err)
+	}
+	return response, nil
+}
+
+// CancelOperation cancels a pending or active operation of a scheduler
+func (c *GRPCClient) CancelOperation(ctx context.Context, request *v1.CancelOperationRequest) (*v1.CancelOperationResponse, error) {
+	response, err := c.operations.CancelOperation(ctx, request)
+	if err != nil {
+		return nil, grpcError("cancel operation", err)
+	}
+	return response, nil
+}
+
+// UpdateRoomWithPing updates a room status, like the game room pings do
+func (c *GRPCClient) UpdateRoomWithPing(ctx context.Context, request *v1.UpdateRoomWithPingRequest) (*v1.UpdateRoomWithPingResponse, error) {
+	response, err := c.rooms.UpdateRoomWithPing(ctx, request)
+	if err != nil {
+		return nil, grpcError("update room with ping", err)
+	}
+	return response, nil
+}
+
+// ForwardRoomEvent forwards a room event to the scheduler forwarders
+func (c *GRPCClient) ForwardRoomEvent(ctx context.Context, request *v1.ForwardRoomEventRequest) (*v1.ForwardRoomEventResponse, error) {
+	response, err := c.rooms.ForwardRoomEvent(ctx, request)
+	if err != nil {
+		return nil, grpcError("forward room event", err)
+	}
+	return response, nil
+}
+
+// ForwardPlayerEvent forwards a player event to the scheduler forwarders
+func (c *GRPCClient) ForwardPlayerEvent(ctx context.Context, request *v1.ForwardPlayerEventRequest) (*v1.ForwardPlayerEventResponse, error) {
+	response, err := c.rooms.ForwardPlayerEvent(ctx, request)
+	if err != nil {
+		return nil, grpcError("forward player event", err)
+	}
+	return response, nil
+}
+
+// UpdateRoomStatus updates a room status
+func (c *GRPCClient) UpdateRoomStatus(ctx context.Context, request *v1.UpdateRoomStatusRequest) (*v1.UpdateRoomStatusResponse, error) {
+	response, err := c.rooms.UpdateRoomStatus(ctx, request)
+	if err != nil {
+		return nil, grpcError("update room status", err)
+	}
+	return response, nil
+}
+
+// grpcError converts the status of a failed call to an APIError, with the
+// HTTP status the gateway would have responded
+func grpcError(operation string, err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("error on %s call: %w", operation, err)
+	}
+
+	apiErr := &APIError{
+		Operation:  operation,
+		StatusCode: runtime.HTTPStatusFromCode(s.Code()),
+		Code:       s.Code(),
+		Message:    s.Message(),
+	}
+	for _, detail := range s.Proto().GetDetails() {
+		bts, err := protojson.Marshal(detail)
+		if err == nil {
+			apiErr.Details = append(apiErr.Details, json.RawMessage(bts))
+		}
+	}
+	return apiErr
+}
@@ -0,0 +1,96 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package maestro
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type schedulersServer struct {
+	v1.UnimplementedSchedulersServiceServer
+}
+
+func (s *schedulersServer) GetScheduler(_ context.Context, request *v1.GetSchedulerRequest) (*v1.GetSchedulerResponse, error) {
+	if request.GetSchedulerName() != "scheduler" {
+		return nil, status.Errorf(codes.NotFound, "scheduler %s not found", request.GetSchedulerName())
+	}
+	return &v1.GetSchedulerResponse{Scheduler: &v1.Scheduler{Name: "scheduler", Spec: &v1.Spec{Version: request.GetVersion()}}}, nil
+}
+
+type operationsServer struct {
+	v1.UnimplementedOperationsServiceServer
+}
+
+func (s *operationsServer) ListOperations(_ context.Context, request *v1.ListOperationsRequest) (*v1.ListOperationsResponse, error) {
+	return &v1.ListOperationsResponse{FinishedOperations: []*v1.Operation{{Id: "abc", SchedulerName: request.GetSchedulerName()}}}, nil
+}
+
+// newBufconnClient serves the fake services in memory and returns a client
+// connected to them
+func newBufconnClient(t *testing.T) *GRPCClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	v1.RegisterSchedulersServiceServer(server, &schedulersServer{})
+	v1.RegisterOperationsServiceServer(server, &operationsServer{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return NewGRPCClient(conn)
+}
+
+func TestGRPCClient(t *testing.T) {
+	ctx := context.Background()
+	client := newBufconnClient(t)
+
+	t.Run("calls the services", func(t *testing.T) {
+		scheduler, err := client.GetScheduler(ctx, &v1.GetSchedulerRequest{SchedulerName: "scheduler", Version: "v1.0.0"})
+		require.NoError(t, err)
+		require.Equal(t, "v1.0.0", scheduler.GetScheduler().GetSpec().GetVersion())
+
+		operations, err := client.ListOperations(ctx, &v1.ListOperationsRequest{SchedulerName: "scheduler"})
+		require.NoError(t, err)
+		require.Len(t, operations.GetFinishedOperations(), 1)
+		require.Equal(t, "scheduler", operations.GetFinishedOperations()[0].GetSchedulerName())
+	})
+
+	t.Run("returns the status as an API error", func(t *testing.T) {
+		_, err := client.GetScheduler(ctx, &v1.GetSchedulerRequest{SchedulerName: "other"})
+
+		var apiErr *APIError
+		require.True(t, errors.As(err, &apiErr))
+		require.Equal(t, codes.NotFound, apiErr.Code)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		require.EqualError(t, err, "get scheduler failed: scheduler other not found")
+	})
+
+	t.Run("returns unimplemented services as an API error", func(t *testing.T) {
+		_, err := client.AddRooms(ctx, &v1.AddRoomsRequest{SchedulerName: "scheduler", Amount: 10})
+
+		var apiErr *APIError
+		require.True(t, errors.As(err, &apiErr))
+		require.Equal(t, codes.Unimplemented, apiErr.Code)
+	})
+}
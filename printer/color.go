@@ -0,0 +1,55 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package printer
+
+import (
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Color is the ANSI color code of a table cell
+type Color string
+
+const (
+	// NoColor prints the cell as it is
+	NoColor Color = ""
+	// Green cells are healthy states
+	Green Color = "32"
+	// Yellow cells are states in progress
+	Yellow Color = "33"
+	// Red cells are failed states
+	Red Color = "31"
+)
+
+// colorEnabled reports whether out is a terminal that should be colored,
+// following https://no-color.org
+func colorEnabled(out io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// SetColor enables or disables the colors of the table cells, by default
+// they are enabled when printing to a terminal and NO_COLOR is not set
+func (p *Printer) SetColor(enabled bool) {
+	p.color = enabled
+}
+
+func (c Color) paint(text string) string {
+	if c == NoColor || text == "" {
+		return text
+	}
+	return "\x1b[" + string(c) + "m" + text + "\x1b[0m"
+}
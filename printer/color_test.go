@@ -0,0 +1,90 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package printer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestColors(t *testing.T) {
+	response := &v1.ListSchedulersResponse{
+		Schedulers: []*v1.SchedulerWithoutSpec{
+			{Name: "scheduler-1", State: "on-error"},
+			{Name: "scheduler-long-name", State: "in-sync"},
+		},
+	}
+	items := []proto.Message{response.Schedulers[0], response.Schedulers[1]}
+	resource := &Resource{
+		Columns: []Column{
+			{
+				Header: "STATE",
+				Value:  func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetState() },
+				Color: func(item proto.Message) Color {
+					if item.(*v1.SchedulerWithoutSpec).GetState() == "on-error" {
+						return Red
+					}
+					return Green
+				},
+			},
+			{Header: "NAME", Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetName() }},
+		},
+	}
+
+	t.Run("colors the cells keeping them aligned", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		p, err := New(Table, out)
+		require.NoError(t, err)
+		p.SetColor(true)
+
+		err = p.Print(response, items, resource)
+
+		require.NoError(t, err)
+		require.Equal(t, "STATE      NAME\n"+
+			"\x1b[31mon-error\x1b[0m   scheduler-1\n"+
+			"\x1b[32min-sync\x1b[0m    scheduler-long-name\n", out.String())
+	})
+
+	t.Run("does not color buffers", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		p, err := New(Table, out)
+		require.NoError(t, err)
+
+		err = p.Print(response, items, resource)
+
+		require.NoError(t, err)
+		require.Equal(t, "STATE      NAME\n"+
+			"on-error   scheduler-1\n"+
+			"in-sync    scheduler-long-name\n", out.String())
+	})
+
+	t.Run("does not color files that are not terminals nor with NO_COLOR", func(t *testing.T) {
+		file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+		require.NoError(t, err)
+		defer file.Close()
+		require.False(t, colorEnabled(file))
+
+		os.Setenv("NO_COLOR", "1")
+		defer os.Unsetenv("NO_COLOR")
+		require.False(t, colorEnabled(os.Stdout))
+
+		p, err := New(Table, file)
+		require.NoError(t, err)
+		require.NoError(t, p.Print(response, items, resource))
+		bts, err := ioutil.ReadFile(file.Name())
+		require.NoError(t, err)
+		require.NotContains(t, string(bts), "\x1b[")
+	})
+}
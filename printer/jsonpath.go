@@ -0,0 +1,581 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a kubectl like JSONPath template, e.g.
+// {range .schedulers[*]}{.name}{"\t"}{.state}{"\n"}{end}. Text out of braces
+// is printed as it is and the results of an expression are separated by
+// spaces. Expressions support fields, [*], .*, indexes, slices, recursive
+// descent (..name) and filters like [?(@.status=="finished")].
+type JSONPath struct {
+	nodes []jsonPathNode
+}
+
+// jsonPathNode is a text, a string literal, an expression or a range
+type jsonPathNode struct {
+	text     string
+	isText   bool
+	path     *jsonPathExpression
+	rangeOf  *jsonPathExpression
+	children []jsonPathNode
+}
+
+// jsonPathExpression is a path like $.schedulers[0].name
+type jsonPathExpression struct {
+	// root is set by expressions starting with $, the others start on the
+	// current value of the range
+	root     bool
+	segments []jsonPathSegment
+}
+
+type jsonPathSegmentKind int
+
+const (
+	segmentField jsonPathSegmentKind = iota
+	segmentWildcard
+	segmentIndex
+	segmentSlice
+	segmentFilter
+)
+
+type jsonPathSegment struct {
+	kind jsonPathSegmentKind
+	// recursive segments apply to the value and all its descendants
+	recursive  bool
+	name       string
+	index      int
+	start, end *int
+	filter     *jsonPathFilter
+}
+
+// jsonPathFilter is the predicate of [?(@.path op literal)], without op it
+// checks that path exists
+type jsonPathFilter struct {
+	path    *jsonPathExpression
+	op      string
+	literal interface{}
+}
+
+// ParseJSONPath parses a JSONPath template, templates without braces are
+// parsed as a single expression
+func ParseJSONPath(template string) (*JSONPath, error) {
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	var stack [][]jsonPathNode
+	var nodes []jsonPathNode
+	for len(template) > 0 {
+		open := strings.Index(template, "{")
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{text: template, isText: true})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{text: template[:open], isText: true})
+		}
+		end, err := closingBrace(template, open)
+		if err != nil {
+			return nil, err
+		}
+		expression := strings.TrimSpace(template[open+1 : end])
+		template = template[end+1:]
+
+		switch {
+		case expression == "end":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("bad jsonpath: {end} without {range}")
+			}
+			parent := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			parent[len(parent)-1].children = nodes
+			nodes = parent
+		case strings.HasPrefix(expression, "range "):
+			path, err := parseJSONPathExpression(strings.TrimSpace(strings.TrimPrefix(expression, "range ")))
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, jsonPathNode{rangeOf: path})
+			stack = append(stack, nodes)
+			nodes = nil
+		case strings.HasPrefix(expression, `"`):
+			text, err := strconv.Unquote(expression)
+			if err != nil {
+				return nil, fmt.Errorf("bad jsonpath string %s: %w", expression, err)
+			}
+			nodes = append(nodes, jsonPathNode{text: text, isText: true})
+		default:
+			path, err := parseJSONPathExpression(expression)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, jsonPathNode{path: path})
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("bad jsonpath: {range} without {end}")
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// closingBrace returns the index of the brace closing the one at open,
+// ignoring braces in quotes
+func closingBrace(template string, open int) (int, error) {
+	var quote byte
+	for i := open + 1; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("bad jsonpath: unclosed { at %d", open)
+}
+
+func parseJSONPathExpression(expression string) (*jsonPathExpression, error) {
+	path := &jsonPathExpression{}
+	s := expression
+	switch {
+	case strings.HasPrefix(s, "$"):
+		path.root = true
+		s = s[1:]
+	case strings.HasPrefix(s, "@"):
+		s = s[1:]
+	}
+
+	for len(s) > 0 {
+		recursive := false
+		switch {
+		case strings.HasPrefix(s, ".."):
+			recursive = true
+			s = s[2:]
+		case s[0] == '.':
+			s = s[1:]
+		case s[0] != '[':
+			return nil, fmt.Errorf("bad jsonpath %q: unexpected %q", expression, s)
+		}
+		if len(s) == 0 {
+			if recursive {
+				return nil, fmt.Errorf("bad jsonpath %q: missing field after ..", expression)
+			}
+			break
+		}
+
+		var segment jsonPathSegment
+		if s[0] == '[' {
+			end, err := closingBracket(s)
+			if err != nil {
+				return nil, fmt.Errorf("bad jsonpath %q: %w", expression, err)
+			}
+			segment, err = parseJSONPathBracket(s[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("bad jsonpath %q: %w", expression, err)
+			}
+			s = s[end+1:]
+		} else {
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			name := s[:end]
+			s = s[end:]
+			segment = jsonPathSegment{kind: segmentField, name: name}
+			if name == "*" {
+				segment = jsonPathSegment{kind: segmentWildcard}
+			}
+		}
+		segment.recursive = recursive
+		path.segments = append(path.segments, segment)
+	}
+	return path, nil
+}
+
+// closingBracket returns the index of the bracket closing s[0], ignoring
+// brackets in quotes and nested ones
+func closingBracket(s string) (int, error) {
+	var quote byte
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed [")
+}
+
+func parseJSONPathBracket(content string) (jsonPathSegment, error) {
+	content = strings.TrimSpace(content)
+	switch {
+	case content == "*":
+		return jsonPathSegment{kind: segmentWildcard}, nil
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		filter, err := parseJSONPathFilter(strings.TrimSpace(content[2 : len(content)-1]))
+		if err != nil {
+			return jsonPathSegment{}, err
+		}
+		return jsonPathSegment{kind: segmentFilter, filter: filter}, nil
+	case strings.HasPrefix(content, "?("):
+		return jsonPathSegment{}, fmt.Errorf("bad filter [%s]: unclosed (", content)
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, `"`):
+		name, err := unquote(content)
+		if err != nil {
+			return jsonPathSegment{}, err
+		}
+		return jsonPathSegment{kind: segmentField, name: name}, nil
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 2)
+		segment := jsonPathSegment{kind: segmentSlice}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return jsonPathSegment{}, fmt.Errorf("bad slice [%s]", content)
+			}
+			if i == 0 {
+				segment.start = &n
+			} else {
+				segment.end = &n
+			}
+		}
+		return segment, nil
+	}
+	n, err := strconv.Atoi(content)
+	if err != nil {
+		return jsonPathSegment{}, fmt.Errorf("bad index [%s]", content)
+	}
+	return jsonPathSegment{kind: segmentIndex, index: n}, nil
+}
+
+// filterOperators are checked in order at each position, so <= matches
+// before <
+var filterOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parseJSONPathFilter(content string) (*jsonPathFilter, error) {
+	left, op, right := content, "", ""
+	if i, operator := filterOperator(content); operator != "" {
+		left, op, right = strings.TrimSpace(content[:i]), operator, strings.TrimSpace(content[i+len(operator):])
+	}
+	if !strings.HasPrefix(left, "@") {
+		return nil, fmt.Errorf("bad filter ?(%s): it must start with @", content)
+	}
+	path, err := parseJSONPathExpression(left)
+	if err != nil {
+		return nil, err
+	}
+	filter := &jsonPathFilter{path: path, op: op}
+	if op == "" {
+		return filter, nil
+	}
+
+	switch {
+	case strings.HasPrefix(right, "'") || strings.HasPrefix(right, `"`):
+		filter.literal, err = unquote(right)
+	case right == "true" || right == "false":
+		filter.literal = right == "true"
+	default:
+		filter.literal, err = strconv.ParseFloat(right, 64)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bad filter ?(%s): bad value %s", content, right)
+	}
+	return filter, nil
+}
+
+// filterOperator returns the first operator of a filter and its index,
+// ignoring the ones in quoted literals
+func filterOperator(content string) (int, string) {
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		default:
+			for _, operator := range filterOperators {
+				if strings.HasPrefix(content[i:], operator) {
+					return i, operator
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+// unquote accepts single and double quoted strings
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") && len(s) >= 2 {
+		s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// Execute writes the template evaluated against data, a value decoded from
+// JSON
+func (j *JSONPath) Execute(w io.Writer, data interface{}) error {
+	return executeJSONPath(w, j.nodes, data, data)
+}
+
+// Find returns the results of a template holding a single expression, e.g.
+// {.metadata.name}
+func (j *JSONPath) Find(data interface{}) ([]interface{}, error) {
+	if len(j.nodes) != 1 || j.nodes[0].path == nil {
+		return nil, fmt.Errorf("jsonpath must be a single expression")
+	}
+	return j.nodes[0].path.evaluate(data, data), nil
+}
+
+func executeJSONPath(w io.Writer, nodes []jsonPathNode, root, current interface{}) error {
+	for _, node := range nodes {
+		switch {
+		case node.isText:
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return err
+			}
+		case node.rangeOf != nil:
+			for _, value := range node.rangeOf.evaluate(root, current) {
+				if err := executeJSONPath(w, node.children, root, value); err != nil {
+					return err
+				}
+			}
+		default:
+			texts := make([]string, 0)
+			for _, value := range node.path.evaluate(root, current) {
+				texts = append(texts, FormatValue(value))
+			}
+			if _, err := io.WriteString(w, strings.Join(texts, " ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *jsonPathExpression) evaluate(root, current interface{}) []interface{} {
+	values := []interface{}{current}
+	if p.root {
+		values = []interface{}{root}
+	}
+	for _, segment := range p.segments {
+		if segment.recursive {
+			values = descendants(values)
+		}
+		next := make([]interface{}, 0)
+		for _, value := range values {
+			next = append(next, segment.apply(root, value)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (s *jsonPathSegment) apply(root, value interface{}) []interface{} {
+	switch s.kind {
+	case segmentField:
+		if m, ok := value.(map[string]interface{}); ok {
+			if field, ok := m[s.name]; ok {
+				return []interface{}{field}
+			}
+		}
+	case segmentWildcard:
+		return children(value)
+	case segmentIndex:
+		if list, ok := value.([]interface{}); ok {
+			i := s.index
+			if i < 0 {
+				i += len(list)
+			}
+			if i >= 0 && i < len(list) {
+				return []interface{}{list[i]}
+			}
+		}
+	case segmentSlice:
+		if list, ok := value.([]interface{}); ok {
+			start, end := 0, len(list)
+			if s.start != nil {
+				start = clampIndex(*s.start, len(list))
+			}
+			if s.end != nil {
+				end = clampIndex(*s.end, len(list))
+			}
+			if start < end {
+				return list[start:end]
+			}
+		}
+	case segmentFilter:
+		if list, ok := value.([]interface{}); ok {
+			matches := make([]interface{}, 0)
+			for _, item := range list {
+				if s.filter.matches(root, item) {
+					matches = append(matches, item)
+				}
+			}
+			return matches
+		}
+	}
+	return nil
+}
+
+func clampIndex(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+// children returns the values of a map, sorted by key, or the items of a
+// list
+func children(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, v[key])
+		}
+		return values
+	case []interface{}:
+		return v
+	}
+	return nil
+}
+
+// descendants returns the values and all their descendants, depth first
+func descendants(values []interface{}) []interface{} {
+	all := make([]interface{}, 0)
+	for _, value := range values {
+		all = append(all, value)
+		all = append(all, descendants(children(value))...)
+	}
+	return all
+}
+
+func (f *jsonPathFilter) matches(root, item interface{}) bool {
+	values := f.path.evaluate(root, item)
+	if f.op == "" {
+		return len(values) > 0
+	}
+	for _, value := range values {
+		if compareValues(value, f.op, f.literal) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareValues compares numbers as numbers and the other values by their
+// text
+func compareValues(value interface{}, op string, literal interface{}) bool {
+	if number, ok := literal.(float64); ok {
+		v, err := strconv.ParseFloat(FormatValue(value), 64)
+		if err != nil {
+			return op == "!="
+		}
+		switch op {
+		case "==":
+			return v == number
+		case "!=":
+			return v != number
+		case "<":
+			return v < number
+		case "<=":
+			return v <= number
+		case ">":
+			return v > number
+		case ">=":
+			return v >= number
+		}
+		return false
+	}
+
+	v, l := FormatValue(value), FormatValue(literal)
+	switch op {
+	case "==":
+		return v == l
+	case "!=":
+		return v != l
+	case "<":
+		return v < l
+	case "<=":
+		return v <= l
+	case ">":
+		return v > l
+	case ">=":
+		return v >= l
+	}
+	return false
+}
+
+// FormatValue returns the text of a value decoded from JSON, maps and lists
+// are printed as JSON and null as an empty string
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	bts, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(bts)
+}
@@ -0,0 +1,133 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPath(t *testing.T) {
+	document := `{
+		"schedulers": [
+			{"name": "scheduler-1", "state": "ready", "roomsReady": 10, "portRange": {"start": 1000, "end": 2000}},
+			{"name": "scheduler-2", "state": "creating", "roomsReady": 0, "portRange": {"start": 3000, "end": 4000}},
+			{"name": "scheduler-3", "state": "ready", "roomsReady": 5, "portRange": null}
+		],
+		"total": 3
+	}`
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	var data interface{}
+	require.NoError(t, decoder.Decode(&data))
+
+	testCases := []struct {
+		Title          string
+		Template       string
+		ExpectedOutput string
+	}{
+		{Title: "field", Template: "{.total}", ExpectedOutput: "3"},
+		{Title: "without braces", Template: ".schedulers[0].name", ExpectedOutput: "scheduler-1"},
+		{Title: "root", Template: "{$.schedulers[1].name}", ExpectedOutput: "scheduler-2"},
+		{Title: "wildcard", Template: "{.schedulers[*].name}", ExpectedOutput: "scheduler-1 scheduler-2 scheduler-3"},
+		{Title: "negative index", Template: "{.schedulers[-1].name}", ExpectedOutput: "scheduler-3"},
+		{Title: "slice", Template: "{.schedulers[1:].name}", ExpectedOutput: "scheduler-2 scheduler-3"},
+		{Title: "quoted field", Template: "{.schedulers[0]['name']}", ExpectedOutput: "scheduler-1"},
+		{Title: "recursive descent", Template: "{..start}", ExpectedOutput: "1000 3000"},
+		{Title: "filter", Template: `{.schedulers[?(@.state=="ready")].name}`, ExpectedOutput: "scheduler-1 scheduler-3"},
+		{Title: "numeric filter", Template: "{.schedulers[?(@.roomsReady>=5)].name}", ExpectedOutput: "scheduler-1 scheduler-3"},
+		{Title: "existence filter", Template: "{.schedulers[?(@.portRange)].name}", ExpectedOutput: "scheduler-1 scheduler-2 scheduler-3"},
+		{Title: "object", Template: "{.schedulers[0].portRange}", ExpectedOutput: `{"end":2000,"start":1000}`},
+		{Title: "missing field", Template: "{.schedulers[0].missing}", ExpectedOutput: ""},
+		{Title: "negative slice start", Template: "{.schedulers[-2:].name}", ExpectedOutput: "scheduler-2 scheduler-3"},
+		{Title: "negative slice end", Template: "{.schedulers[:-1].name}", ExpectedOutput: "scheduler-1 scheduler-2"},
+		{Title: "slice start before the list", Template: "{.schedulers[-10:1].name}", ExpectedOutput: "scheduler-1"},
+		{Title: "slice end after the list", Template: "{.schedulers[1:10].name}", ExpectedOutput: "scheduler-2 scheduler-3"},
+		{Title: "slice after the list", Template: "{.schedulers[5:].name}", ExpectedOutput: ""},
+		{Title: "empty slice", Template: "{.schedulers[2:1].name}", ExpectedOutput: ""},
+		{Title: "index after the list", Template: "{.schedulers[3].name}", ExpectedOutput: ""},
+		{Title: "negative index before the list", Template: "{.schedulers[-4].name}", ExpectedOutput: ""},
+		{Title: "filter with an operator in the string literal", Template: `{.schedulers[?(@.state<"d==b")].name}`, ExpectedOutput: "scheduler-2"},
+		{Title: "filter with a quoted operator equal to the field", Template: `{.schedulers[?(@.name!='a<b')].name}`, ExpectedOutput: "scheduler-1 scheduler-2 scheduler-3"},
+		{Title: "filter comparing a missing field", Template: `{.schedulers[?(@.missing=="x")].name}`, ExpectedOutput: ""},
+		{Title: "filter excluding a missing field", Template: `{.schedulers[?(@.missing!="x")].name}`, ExpectedOutput: ""},
+		{Title: "numeric filter on a null parent", Template: "{.schedulers[?(@.portRange.start>=1000)].name}", ExpectedOutput: "scheduler-1 scheduler-2"},
+		{
+			Title:          "range with text and literals",
+			Template:       `schedulers: {range .schedulers[*]}{.name}{"\t"}{.state}{"\n"}{end}`,
+			ExpectedOutput: "schedulers: scheduler-1\tready\nscheduler-2\tcreating\nscheduler-3\tready\n",
+		},
+		{
+			Title:          "nested range",
+			Template:       `{range .schedulers[0:2]}{range .portRange.*}{@};{end}{end}`,
+			ExpectedOutput: "2000;1000;4000;3000;",
+		},
+		{
+			Title:          "nested range over a filter with text between the ends",
+			Template:       `{range .schedulers[?(@.state=="ready")]}{.name}={range .portRange.*}{@},{end};{end}`,
+			ExpectedOutput: "scheduler-1=2000,1000,;scheduler-3=;",
+		},
+		{
+			Title:          "root in a range",
+			Template:       "{range .schedulers[0:2]}{.name}/{$.total} {end}",
+			ExpectedOutput: "scheduler-1/3 scheduler-2/3 ",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Title, func(t *testing.T) {
+			j, err := ParseJSONPath(testCase.Template)
+			require.NoError(t, err)
+			out := new(bytes.Buffer)
+
+			err = j.Execute(out, data)
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.ExpectedOutput, out.String())
+		})
+	}
+
+	t.Run("finds the results of an expression", func(t *testing.T) {
+		j, err := ParseJSONPath("{.schedulers[*].roomsReady}")
+		require.NoError(t, err)
+
+		results, err := j.Find(data)
+
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{json.Number("10"), json.Number("0"), json.Number("5")}, results)
+	})
+
+	t.Run("fails on bad templates", func(t *testing.T) {
+		for template, expectedError := range map[string]string{
+			"{range .schedulers[*]}{.name}":    "bad jsonpath: {range} without {end}",
+			"{.name}{end}":                     "bad jsonpath: {end} without {range}",
+			"{.schedulers[0}":                  `bad jsonpath ".schedulers[0": unclosed [`,
+			"names: {.name":                    "bad jsonpath: unclosed { at 7",
+			"{.schedulers[a]}":                 `bad jsonpath ".schedulers[a]": bad index [a]`,
+			"{.schedulers[?(.state=='a')]}":    `bad jsonpath ".schedulers[?(.state=='a')]": bad filter ?(.state=='a'): it must start with @`,
+			"{":                                "bad jsonpath: unclosed { at 0",
+			`{"unterminated}`:                  "bad jsonpath: unclosed { at 0",
+			"{end}":                            "bad jsonpath: {end} without {range}",
+			"{range .schedulers[*]}{end}{end}": "bad jsonpath: {end} without {range}",
+			"{range .schedulers[*]}{range .portRange}{end}": "bad jsonpath: {range} without {end}",
+			"{..}":                          `bad jsonpath "..": missing field after ..`,
+			"{.schedulers[1:a]}":            `bad jsonpath ".schedulers[1:a]": bad slice [1:a]`,
+			"{.schedulers[?(@.state==)]}":   `bad jsonpath ".schedulers[?(@.state==)]": bad filter ?(@.state==): bad value `,
+			`{.schedulers[?(@.state=="a"]}`: `bad jsonpath ".schedulers[?(@.state==\"a\"]": bad filter [?(@.state=="a"]: unclosed (`,
+			"{.schedulers[?(@.state=='a')}": `bad jsonpath ".schedulers[?(@.state=='a')": unclosed [`,
+		} {
+			_, err := ParseJSONPath(template)
+
+			require.EqualError(t, err, expectedError, template)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+// Package printer prints the responses of the get commands in the format
+// chosen by --output
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/topfreegames/maestro-cli/formats"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/yaml"
+)
+
+// The formats of --output, see package formats
+const (
+	Table          = formats.Table
+	Wide           = formats.Wide
+	JSON           = formats.JSON
+	YAML           = formats.YAML
+	Name           = formats.Name
+	CSV            = formats.CSV
+	Markdown       = formats.Markdown
+	GoTemplate     = formats.GoTemplate
+	GoTemplateFile = formats.GoTemplateFile
+	JSONPathFormat = formats.JSONPathFormat
+	JSONPathFile   = formats.JSONPathFile
+)
+
+// Formats are the values accepted by --output
+var Formats = formats.Formats
+
+// TemplateFormats are the formats of --output followed by =template
+var TemplateFormats = formats.TemplateFormats
+
+// Column is a column of the table and wide formats
+type Column struct {
+	Header string
+	// Wide columns are only printed by the wide format
+	Wide bool
+	// Field is the JSONPath of the item field shown by the column, used to
+	// sort by the column
+	Field string
+	Value func(item proto.Message) string
+	// Color returns the color of the item cell on terminals, when set
+	Color func(item proto.Message) Color
+}
+
+// Resource describes how the items of a response are printed
+type Resource struct {
+	Columns []Column
+	// Message is an item of the resource, only its fields are accepted by
+	// the JSONPaths of the table options when set
+	Message proto.Message
+	// Name returns the name of an item printed by the name format
+	Name func(item proto.Message) string
+	// Empty is printed by the table formats instead of a table without rows,
+	// when set
+	Empty string
+}
+
+// Printer prints responses to out in one of Formats or TemplateFormats
+type Printer struct {
+	format string
+	out    io.Writer
+	// template prints the JSON form of the response on template formats
+	template func(w io.Writer, data interface{}) error
+	// table are the options of the table formats, set by SetTableOptions
+	table *table
+	// color enables the colors of the table cells
+	color bool
+}
+
+// New ctor, it fails on formats not in Formats and on template formats with
+// bad templates
+func New(format string, out io.Writer) (*Printer, error) {
+	for _, f := range Formats {
+		if format == f {
+			return &Printer{format: format, out: out, color: colorEnabled(out)}, nil
+		}
+	}
+
+	name, text := format, ""
+	if i := strings.Index(format, "="); i >= 0 {
+		name, text = format[:i], format[i+1:]
+	}
+	template, err := parseTemplate(name, text)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, fmt.Errorf("bad output format %q, use one of %s, or %s=TEMPLATE", format, strings.Join(Formats, ", "), strings.Join(TemplateFormats, "|"))
+	}
+	return &Printer{format: name, out: out, template: template}, nil
+}
+
+// Format returns the format of the printer
+func (p *Printer) Format() string {
+	return p.format
+}
+
+// Print prints the response as JSON or YAML, or its items as described by
+// resource on the other formats
+func (p *Printer) Print(response proto.Message, items []proto.Message, resource *Resource) error {
+	if p.template != nil {
+		data, err := jsonValue(response)
+		if err != nil {
+			return err
+		}
+		return p.template(p.out, data)
+	}
+
+	switch p.format {
+	case JSON:
+		bts, err := MarshalJSON(response)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.out, "%s\n", bts)
+		return err
+	case YAML:
+		bts, err := MarshalYAML(response)
+		if err != nil {
+			return err
+		}
+		_, err = p.out.Write(bts)
+		return err
+	case Name:
+		for _, item := range p.sortItems(items) {
+			fmt.Fprintln(p.out, resource.Name(item))
+		}
+		return nil
+	}
+	return p.printTable(items, resource)
+}
+
+// MarshalJSON marshals message with protojson, every field included, indented
+// with two spaces
+func MarshalJSON(message proto.Message) ([]byte, error) {
+	bts, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling %s: %w", message.ProtoReflect().Descriptor().FullName(), err)
+	}
+	// protojson output is unstable on purpose, indenting normalizes it
+	indented := new(bytes.Buffer)
+	err = json.Indent(indented, bts, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
+
+// MarshalYAML marshals message as YAML, with the field names of MarshalJSON
+func MarshalYAML(message proto.Message) ([]byte, error) {
+	bts, err := MarshalJSON(message)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(bts)
+}
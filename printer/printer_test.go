@@ -0,0 +1,149 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package printer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPrinter(t *testing.T) {
+	response := &v1.ListSchedulersResponse{
+		Schedulers: []*v1.SchedulerWithoutSpec{
+			{Name: "scheduler-1", Game: "game", State: "ready"},
+			{Name: "scheduler-2", Game: "other-game", State: "creating"},
+		},
+	}
+	items := []proto.Message{response.Schedulers[0], response.Schedulers[1]}
+	resource := &Resource{
+		Columns: []Column{
+			{Header: "NAME", Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetName() }},
+			{Header: "STATE", Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetState() }},
+			{Header: "GAME", Wide: true, Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetGame() }},
+		},
+		Name:  func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetName() },
+		Empty: "no schedulers found",
+	}
+
+	testCases := []struct {
+		Title          string
+		Format         string
+		Items          []proto.Message
+		ExpectedOutput string
+	}{
+		{
+			Title:  "table",
+			Format: Table,
+			Items:  items,
+			ExpectedOutput: "NAME          STATE\n" +
+				"scheduler-1   ready\n" +
+				"scheduler-2   creating\n",
+		}, {
+			Title:  "wide",
+			Format: Wide,
+			Items:  items,
+			ExpectedOutput: "NAME          STATE      GAME\n" +
+				"scheduler-1   ready      game\n" +
+				"scheduler-2   creating   other-game\n",
+		}, {
+			Title:          "table without items",
+			Format:         Table,
+			ExpectedOutput: "no schedulers found\n",
+		}, {
+			Title:          "name",
+			Format:         Name,
+			Items:          items,
+			ExpectedOutput: "scheduler-1\nscheduler-2\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Title, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			p, err := New(testCase.Format, out)
+			require.NoError(t, err)
+
+			err = p.Print(response, testCase.Items, resource)
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.ExpectedOutput, out.String())
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		p, err := New(JSON, out)
+		require.NoError(t, err)
+
+		err = p.Print(&v1.ListSchedulersResponse{Schedulers: []*v1.SchedulerWithoutSpec{{Name: "scheduler-1"}}}, nil, resource)
+
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "{\n  \"schedulers\": [\n    {\n      \"name\": \"scheduler-1\",\n")
+		require.Contains(t, out.String(), "      \"maxSurge\": \"\"\n")
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		p, err := New(YAML, out)
+		require.NoError(t, err)
+
+		err = p.Print(response, items, resource)
+
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "schedulers:\n- createdAt: null\n  game: game\n")
+		require.Contains(t, out.String(), "  name: scheduler-2\n")
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		templateFile := filepath.Join(t.TempDir(), "template.txt")
+		require.NoError(t, ioutil.WriteFile(templateFile, []byte(`{range .schedulers[*]}{.name}={.state}{"\n"}{end}`), 0600))
+		goTemplateFile := filepath.Join(t.TempDir(), "template.tmpl")
+		require.NoError(t, ioutil.WriteFile(goTemplateFile, []byte(`{{range .schedulers}}{{.game}}{{"\n"}}{{end}}`), 0600))
+
+		for format, expectedOutput := range map[string]string{
+			`go-template={{range .schedulers}}{{.name}} {{end}}`: "scheduler-1 scheduler-2 ",
+			"go-template-file=" + goTemplateFile:                 "game\nother-game\n",
+			"jsonpath={.schedulers[*].name}":                     "scheduler-1 scheduler-2",
+			"jsonpath-file=" + templateFile:                      "scheduler-1=ready\nscheduler-2=creating\n",
+		} {
+			out := new(bytes.Buffer)
+			p, err := New(format, out)
+			require.NoError(t, err)
+
+			err = p.Print(response, items, resource)
+
+			require.NoError(t, err)
+			require.Equal(t, expectedOutput, out.String(), format)
+		}
+	})
+
+	t.Run("fails on templates formats without template", func(t *testing.T) {
+		_, err := New("jsonpath", new(bytes.Buffer))
+		require.EqualError(t, err, "jsonpath needs a template, e.g. -o jsonpath='{.schedulers[*].name}'")
+
+		_, err = New("go-template={{.name", new(bytes.Buffer))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error parsing go-template")
+
+		_, err = New("go-template-file=missing.tmpl", new(bytes.Buffer))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error reading go-template-file")
+	})
+
+	t.Run("fails on unknown formats", func(t *testing.T) {
+		_, err := New("xml", new(bytes.Buffer))
+
+		require.EqualError(t, err, "bad output format \"xml\", use one of table, wide, json, yaml, name, csv, markdown, or go-template|go-template-file|jsonpath|jsonpath-file=TEMPLATE")
+	})
+}
@@ -0,0 +1,441 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package printer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TableOptions customize the table, wide, csv and markdown formats, SortBy
+// and Reverse apply to the name format too
+type TableOptions struct {
+	// Columns replace the columns of the resource, as comma separated
+	// NAME:JSONPATH pairs evaluated against the JSON form of every item,
+	// e.g. NAME:.name,ROOMS:.roomsReady
+	Columns string
+	// SortBy sorts the items by a column, e.g. AGE, or by a JSONPath of
+	// the items, e.g. .createdAt
+	SortBy string
+	// Reverse reverses the order of the items
+	Reverse bool
+	// NoHeaders omits the header line
+	NoHeaders bool
+}
+
+// table are the parsed table options of a printer
+type table struct {
+	columns   []Column
+	sortKey   func(item proto.Message) interface{}
+	reverse   bool
+	noHeaders bool
+}
+
+// SetTableOptions sets the table options of the printer, the JSONPaths of
+// options must only use fields of the resource items
+func (p *Printer) SetTableOptions(options *TableOptions, resource *Resource) error {
+	var message protoreflect.MessageDescriptor
+	if resource.Message != nil {
+		message = resource.Message.ProtoReflect().Descriptor()
+	}
+
+	t := &table{reverse: options.Reverse, noHeaders: options.NoHeaders}
+	if options.Columns != "" {
+		columns, err := parseColumns(options.Columns, message)
+		if err != nil {
+			return err
+		}
+		t.columns = columns
+	}
+	if options.SortBy != "" {
+		columns := t.columns
+		if columns == nil {
+			columns = resource.Columns
+		}
+		sortKey, err := parseSortBy(options.SortBy, columns, message)
+		if err != nil {
+			return err
+		}
+		t.sortKey = sortKey
+	}
+	p.table = t
+	return nil
+}
+
+// sortItems returns a copy of items in the order of the table options
+func (p *Printer) sortItems(items []proto.Message) []proto.Message {
+	sorted := append([]proto.Message{}, items...)
+	if p.table == nil {
+		return sorted
+	}
+	if p.table.sortKey != nil {
+		keys := make(map[proto.Message]interface{}, len(sorted))
+		for _, item := range sorted {
+			keys[item] = p.table.sortKey(item)
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return compareSortKeys(keys[sorted[i]], keys[sorted[j]]) < 0
+		})
+	}
+	if p.table.reverse {
+		for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		}
+	}
+	return sorted
+}
+
+func (p *Printer) printTable(items []proto.Message, resource *Resource) error {
+	if len(items) == 0 && resource.Empty != "" && (p.format == Table || p.format == Wide) {
+		_, err := fmt.Fprintln(p.out, resource.Empty)
+		return err
+	}
+
+	var columns []Column
+	if p.table != nil && p.table.columns != nil {
+		columns = p.table.columns
+	} else {
+		for _, column := range resource.Columns {
+			if !column.Wide || p.format == Wide {
+				columns = append(columns, column)
+			}
+		}
+	}
+
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, column.Header)
+	}
+	rows := make([][]string, 0, len(items))
+	colors := make([][]Color, 0, len(items))
+	for _, item := range p.sortItems(items) {
+		values := make([]string, 0, len(columns))
+		rowColors := make([]Color, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, column.Value(item))
+			color := NoColor
+			if p.color && column.Color != nil && (p.format == Table || p.format == Wide) {
+				color = column.Color(item)
+			}
+			rowColors = append(rowColors, color)
+		}
+		rows = append(rows, values)
+		colors = append(colors, rowColors)
+	}
+
+	noHeaders := p.table != nil && p.table.noHeaders
+	switch p.format {
+	case CSV:
+		if !noHeaders {
+			rows = append([][]string{headers}, rows...)
+		}
+		return writeCSV(p.out, rows)
+	case Markdown:
+		// markdown tables need the headers
+		return writeMarkdown(p.out, headers, rows)
+	}
+	if !noHeaders {
+		rows = append([][]string{headers}, rows...)
+		colors = append([][]Color{nil}, colors...)
+	}
+	return writeRows(p.out, rows, colors)
+}
+
+// writeRows writes the cells aligned in columns separated by three spaces,
+// like a tabwriter, measuring the cells without their colors
+func writeRows(out io.Writer, rows [][]string, colors [][]Color) error {
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	var b strings.Builder
+	for r, row := range rows {
+		for i, cell := range row {
+			// the headers have no colors
+			if colors[r] != nil {
+				b.WriteString(colors[r][i].paint(cell))
+			} else {
+				b.WriteString(cell)
+			}
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+3))
+			}
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// parseColumns parses the NAME:JSONPATH pairs of --columns
+func parseColumns(spec string, message protoreflect.MessageDescriptor) ([]Column, error) {
+	columns := make([]Column, 0)
+	for _, pair := range splitColumns(spec) {
+		i := strings.Index(pair, ":")
+		if i <= 0 || i == len(pair)-1 {
+			return nil, fmt.Errorf("bad column %q, use NAME:JSONPATH, e.g. NAME:.name", pair)
+		}
+		header, path := strings.TrimSpace(pair[:i]), strings.TrimSpace(pair[i+1:])
+		expression, err := parseItemPath(path, message)
+		if err != nil {
+			return nil, fmt.Errorf("bad column %q: %w", pair, err)
+		}
+		columns = append(columns, Column{
+			Header: header,
+			Field:  path,
+			Value: func(item proto.Message) string {
+				texts := make([]string, 0)
+				for _, value := range evaluateItem(expression, item) {
+					// unset messages are null on the JSON form
+					if value != nil {
+						texts = append(texts, FormatValue(value))
+					}
+				}
+				if len(texts) == 0 {
+					return "-"
+				}
+				return strings.Join(texts, ",")
+			},
+		})
+	}
+	return columns, nil
+}
+
+// splitColumns splits spec on the commas out of brackets and quotes, so
+// filters like [?(@.status=="a,b")] are kept whole
+func splitColumns(spec string) []string {
+	var pairs []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(spec); i++ {
+		c := spec[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '(' || c == '{':
+			depth++
+		case c == ']' || c == ')' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			pairs = append(pairs, spec[start:i])
+			start = i + 1
+		}
+	}
+	return append(pairs, spec[start:])
+}
+
+// parseSortBy returns the sort key of --sort-by, a column header, matched
+// ignoring case, or a JSONPath of the items. Columns are sorted by their
+// Field and by their text when it is not set.
+func parseSortBy(sortBy string, columns []Column, message protoreflect.MessageDescriptor) (func(item proto.Message) interface{}, error) {
+	for _, column := range columns {
+		if !strings.EqualFold(column.Header, sortBy) {
+			continue
+		}
+		if column.Field == "" {
+			value := column.Value
+			return func(item proto.Message) interface{} { return value(item) }, nil
+		}
+		sortBy = column.Field
+		break
+	}
+
+	if !strings.HasPrefix(sortBy, ".") && !strings.HasPrefix(sortBy, "{") && !strings.HasPrefix(sortBy, "$") {
+		headers := make([]string, 0, len(columns))
+		for _, column := range columns {
+			headers = append(headers, column.Header)
+		}
+		return nil, fmt.Errorf("bad sort field %q, use one of the columns %s or a JSONPath of the items, e.g. .name", sortBy, strings.Join(headers, ", "))
+	}
+	expression, err := parseItemPath(sortBy, message)
+	if err != nil {
+		return nil, fmt.Errorf("bad sort field %q: %w", sortBy, err)
+	}
+	return func(item proto.Message) interface{} {
+		values := evaluateItem(expression, item)
+		if len(values) == 0 {
+			return nil
+		}
+		return values[0]
+	}, nil
+}
+
+// parseItemPath parses a single expression JSONPath checking that it only
+// uses fields of message, when message is not nil
+func parseItemPath(path string, message protoreflect.MessageDescriptor) (*jsonPathExpression, error) {
+	template, err := ParseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(template.nodes) != 1 || template.nodes[0].path == nil {
+		return nil, fmt.Errorf("jsonpath must be a single expression")
+	}
+	expression := template.nodes[0].path
+	if message != nil {
+		err = checkFields(expression, message)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expression, nil
+}
+
+// checkFields checks the fields of expression against message. Segments
+// after a recursive descent, a wildcard on a message or a field holding a
+// well known type, like timestamps, are not checked.
+func checkFields(expression *jsonPathExpression, message protoreflect.MessageDescriptor) error {
+	// list is set when the current value is a repeated or map field, whose
+	// items are message values
+	list := false
+	for _, segment := range expression.segments {
+		if segment.recursive || message == nil {
+			return nil
+		}
+		if segment.kind != segmentField {
+			if !list {
+				return nil
+			}
+			list = false
+			continue
+		}
+		if list {
+			return nil
+		}
+
+		field := message.Fields().ByJSONName(segment.name)
+		if field == nil {
+			names := make([]string, 0, message.Fields().Len())
+			for i := 0; i < message.Fields().Len(); i++ {
+				names = append(names, message.Fields().Get(i).JSONName())
+			}
+			return fmt.Errorf("unknown field %q of %s, use one of %s", segment.name, message.Name(), strings.Join(names, ", "))
+		}
+		switch {
+		case field.IsMap():
+			list, message = true, field.MapValue().Message()
+		case field.IsList():
+			list, message = true, field.Message()
+		default:
+			message = field.Message()
+		}
+		if message != nil && strings.HasPrefix(string(message.FullName()), "google.protobuf.") {
+			message = nil
+		}
+	}
+	return nil
+}
+
+// evaluateItem evaluates expression against the JSON form of item
+func evaluateItem(expression *jsonPathExpression, item proto.Message) []interface{} {
+	value, err := jsonValue(item)
+	if err != nil {
+		return nil
+	}
+	return expression.evaluate(value, value)
+}
+
+// compareSortKeys compares numbers as numbers, timestamps as times and the
+// other values by their text, missing values come first
+func compareSortKeys(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	textA, textB := FormatValue(a), FormatValue(b)
+	numberA, errA := strconv.ParseFloat(textA, 64)
+	numberB, errB := strconv.ParseFloat(textB, 64)
+	if errA == nil && errB == nil {
+		return compareOrdered(numberA < numberB, numberA > numberB)
+	}
+	timeA, errA := time.Parse(time.RFC3339Nano, textA)
+	timeB, errB := time.Parse(time.RFC3339Nano, textB)
+	if errA == nil && errB == nil {
+		return compareOrdered(timeA.Before(timeB), timeA.After(timeB))
+	}
+	return strings.Compare(textA, textB)
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// writeCSV writes the rows as comma separated values, quoting the cells
+// with commas, quotes or line breaks
+func writeCSV(out io.Writer, rows [][]string) error {
+	w := csv.NewWriter(out)
+	err := w.WriteAll(rows)
+	if err != nil {
+		return fmt.Errorf("error writing csv: %w", err)
+	}
+	return nil
+}
+
+// markdownEscaper escapes the characters breaking markdown table cells
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+// writeMarkdown writes the rows as a markdown table
+func writeMarkdown(out io.Writer, headers []string, rows [][]string) error {
+	var b strings.Builder
+	writeLine := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			b.WriteString(" " + markdownEscaper.Replace(cell) + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeLine(headers)
+	separators := make([]string, 0, len(headers))
+	for range headers {
+		separators = append(separators, "---")
+	}
+	writeLine(separators)
+	for _, row := range rows {
+		writeLine(row)
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
@@ -0,0 +1,187 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "github.com/topfreegames/maestro/pkg/api/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTableOptions(t *testing.T) {
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	response := &v1.ListSchedulersResponse{
+		Schedulers: []*v1.SchedulerWithoutSpec{
+			{Name: "scheduler-b", Game: "game", State: "ready", CreatedAt: timestamppb.New(now), PortRange: &v1.PortRange{Start: 10000, End: 10010}},
+			{Name: "scheduler-c", Game: "game", State: "creating", CreatedAt: timestamppb.New(now.Add(-time.Hour)), PortRange: &v1.PortRange{Start: 9000, End: 9010}},
+			{Name: "scheduler-a", Game: "other-game", State: "ready", CreatedAt: timestamppb.New(now.Add(500 * time.Millisecond))},
+		},
+	}
+	items := []proto.Message{response.Schedulers[0], response.Schedulers[1], response.Schedulers[2]}
+	resource := &Resource{
+		Message: &v1.SchedulerWithoutSpec{},
+		Columns: []Column{
+			{Header: "NAME", Field: ".name", Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetName() }},
+			{Header: "STATE", Value: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetState() }},
+			{Header: "AGE", Field: ".createdAt", Value: func(item proto.Message) string { return "-" }},
+		},
+		Name: func(item proto.Message) string { return item.(*v1.SchedulerWithoutSpec).GetName() },
+	}
+
+	testCases := []struct {
+		Title          string
+		Format         string
+		Options        TableOptions
+		ExpectedOutput string
+	}{
+		{
+			Title:   "custom columns",
+			Format:  Table,
+			Options: TableOptions{Columns: "NAME:.name,PORTS:{.portRange.start},MISSING:.maxSurge"},
+			ExpectedOutput: "NAME          PORTS   MISSING\n" +
+				"scheduler-b   10000   \n" +
+				"scheduler-c   9000    \n" +
+				"scheduler-a   -       \n",
+		}, {
+			Title:   "custom columns of messages on wide",
+			Format:  Wide,
+			Options: TableOptions{Columns: "NAME:.name,RANGE:.portRange"},
+			ExpectedOutput: "NAME          RANGE\n" +
+				"scheduler-b   {\"end\":10010,\"start\":10000}\n" +
+				"scheduler-c   {\"end\":9010,\"start\":9000}\n" +
+				"scheduler-a   -\n",
+		}, {
+			Title:   "sort by column field",
+			Format:  Table,
+			Options: TableOptions{SortBy: "name"},
+			ExpectedOutput: "NAME          STATE      AGE\n" +
+				"scheduler-a   ready      -\n" +
+				"scheduler-b   ready      -\n" +
+				"scheduler-c   creating   -\n",
+		}, {
+			Title:   "sort by column text",
+			Format:  Table,
+			Options: TableOptions{SortBy: "STATE", NoHeaders: true},
+			ExpectedOutput: "scheduler-c   creating   -\n" +
+				"scheduler-b   ready      -\n" +
+				"scheduler-a   ready      -\n",
+		}, {
+			Title:          "sort by timestamps",
+			Format:         Name,
+			Options:        TableOptions{SortBy: "AGE"},
+			ExpectedOutput: "scheduler-c\nscheduler-b\nscheduler-a\n",
+		}, {
+			Title:          "sort by numbers with missing values first, reversed",
+			Format:         Name,
+			Options:        TableOptions{SortBy: ".portRange.start", Reverse: true},
+			ExpectedOutput: "scheduler-b\nscheduler-c\nscheduler-a\n",
+		}, {
+			Title:          "reverse",
+			Format:         Name,
+			Options:        TableOptions{Reverse: true},
+			ExpectedOutput: "scheduler-a\nscheduler-c\nscheduler-b\n",
+		}, {
+			Title:   "sort by custom column",
+			Format:  Table,
+			Options: TableOptions{Columns: "GAME:.game,NAME:.name", SortBy: "NAME", NoHeaders: true},
+			ExpectedOutput: "other-game   scheduler-a\n" +
+				"game         scheduler-b\n" +
+				"game         scheduler-c\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Title, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			p, err := New(testCase.Format, out)
+			require.NoError(t, err)
+			require.NoError(t, p.SetTableOptions(&testCase.Options, resource))
+
+			err = p.Print(response, items, resource)
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.ExpectedOutput, out.String())
+			require.Equal(t, "scheduler-b", response.Schedulers[0].Name, "the response is not sorted")
+		})
+	}
+
+	t.Run("csv and markdown escape the cells", func(t *testing.T) {
+		escaped := []proto.Message{&v1.SchedulerWithoutSpec{Name: `scheduler "b"`, State: "a|b\\c\nd"}}
+		for format, expectedOutput := range map[string]string{
+			CSV: "NAME,STATE,AGE\n" +
+				"\"scheduler \"\"b\"\"\",\"a|b\\c\nd\",-\n",
+			Markdown: "| NAME | STATE | AGE |\n" +
+				"| --- | --- | --- |\n" +
+				"| scheduler \"b\" | a\\|b\\\\c<br>d | - |\n",
+		} {
+			out := new(bytes.Buffer)
+			p, err := New(format, out)
+			require.NoError(t, err)
+			p.SetColor(true)
+
+			err = p.Print(response, escaped, resource)
+
+			require.NoError(t, err)
+			require.Equal(t, expectedOutput, out.String(), format)
+		}
+	})
+
+	t.Run("csv and markdown use the table options", func(t *testing.T) {
+		for format, expectedOutput := range map[string]string{
+			CSV: "scheduler-a,other-game\nscheduler-b,game\nscheduler-c,game\n",
+			Markdown: "| NAME | GAME |\n" +
+				"| --- | --- |\n" +
+				"| scheduler-a | other-game |\n" +
+				"| scheduler-b | game |\n" +
+				"| scheduler-c | game |\n",
+		} {
+			out := new(bytes.Buffer)
+			p, err := New(format, out)
+			require.NoError(t, err)
+			require.NoError(t, p.SetTableOptions(&TableOptions{Columns: "NAME:.name,GAME:.game", SortBy: "NAME", NoHeaders: true}, resource))
+
+			err = p.Print(response, items, resource)
+
+			require.NoError(t, err)
+			require.Equal(t, expectedOutput, out.String(), format)
+		}
+	})
+
+	t.Run("fails on bad options", func(t *testing.T) {
+		for options, expectedError := range map[TableOptions]string{
+			{Columns: "NAME"}:                      `bad column "NAME", use NAME:JSONPATH, e.g. NAME:.name`,
+			{Columns: "NAME:.name,VERSION:.ver"}:   `bad column "VERSION:.ver": unknown field "ver" of SchedulerWithoutSpec, use one of name, game, state, version, portRange, createdAt, maxSurge`,
+			{Columns: "PORTS:.portRange.first"}:    `bad column "PORTS:.portRange.first": unknown field "first" of PortRange, use one of start, end`,
+			{Columns: "NAME:{.name}{.game}"}:       `bad column "NAME:{.name}{.game}": jsonpath must be a single expression`,
+			{SortBy: "LEASE"}:                      `bad sort field "LEASE", use one of the columns NAME, STATE, AGE or a JSONPath of the items, e.g. .name`,
+			{SortBy: ".status"}:                    `bad sort field ".status": unknown field "status" of SchedulerWithoutSpec, use one of name, game, state, version, portRange, createdAt, maxSurge`,
+			{Columns: "GAME:.game", SortBy: "AGE"}: `bad sort field "AGE", use one of the columns GAME or a JSONPath of the items, e.g. .name`,
+		} {
+			p, err := New(Table, new(bytes.Buffer))
+			require.NoError(t, err)
+
+			err = p.SetTableOptions(&options, resource)
+
+			require.EqualError(t, err, expectedError)
+		}
+	})
+
+	t.Run("does not check the fields of well known types and recursive paths", func(t *testing.T) {
+		p, err := New(Table, new(bytes.Buffer))
+		require.NoError(t, err)
+
+		err = p.SetTableOptions(&TableOptions{Columns: "SECONDS:.createdAt.seconds,ANY:..anything"}, resource)
+
+		require.NoError(t, err)
+	})
+}
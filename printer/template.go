@@ -0,0 +1,77 @@
+// maestro-cli
+// https://github.com/topfreegames/maestro-cli
+//
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2017 Top Free Games <backend@tfgco.com>
+
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"text/template"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// parseTemplate returns the function printing data with the template of a
+// template format, nil when format is not one of TemplateFormats
+func parseTemplate(format, text string) (func(io.Writer, interface{}) error, error) {
+	switch format {
+	case GoTemplateFile, JSONPathFile:
+		if text == "" {
+			return nil, fmt.Errorf("%s needs a file, e.g. -o %s=template.txt", format, format)
+		}
+		bts, err := ioutil.ReadFile(text)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", format, err)
+		}
+		text = string(bts)
+	case GoTemplate, JSONPathFormat:
+		if text == "" {
+			return nil, fmt.Errorf("%s needs a template, e.g. -o %s='%s'", format, format, templateExamples[format])
+		}
+	default:
+		return nil, nil
+	}
+
+	switch format {
+	case GoTemplate, GoTemplateFile:
+		t, err := template.New("output").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", format, err)
+		}
+		return t.Execute, nil
+	}
+	j, err := ParseJSONPath(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", format, err)
+	}
+	return j.Execute, nil
+}
+
+var templateExamples = map[string]string{
+	GoTemplate:     `{{range .schedulers}}{{.name}}{{"\n"}}{{end}}`,
+	JSONPathFormat: `{.schedulers[*].name}`,
+}
+
+// jsonValue returns the JSON form of message decoded into maps and lists,
+// numbers are kept as json.Number so they are printed as they are
+func jsonValue(message proto.Message) (interface{}, error) {
+	bts, err := MarshalJSON(message)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(bts))
+	decoder.UseNumber()
+	var value interface{}
+	err = decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}